@@ -1,68 +1,270 @@
 package main
 
 import (
+	"context"
 	"cool-games/config"
-	"fmt"
+	"cool-games/internal/domain"
+	"cool-games/internal/logging"
+	"cool-games/internal/metrics"
+	"cool-games/internal/tracing"
+	"cool-games/internal/middleware"
+	"cool-games/migrations"
+	"errors"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	gameDelivery "cool-games/internal/game/delivery"
 	gameRepo "cool-games/internal/game/repository"
 	gameUcase "cool-games/internal/game/usecase"
 
-	authDelivery "cool-games/internal/auth/delivery"
 	authRepo "cool-games/internal/auth/repository"
 	authUcase "cool-games/internal/auth/usecase"
 
-	orderDelivery "cool-games/internal/order/delivery"
 	orderRepo "cool-games/internal/order/repository"
 	orderUcase "cool-games/internal/order/usecase"
 
-	genreDelivery "cool-games/internal/genre/delivery"
     genreRepo "cool-games/internal/genre/repository"
     genreUcase "cool-games/internal/genre/usecase"
 
+	"cool-games/internal/notification/notifier"
+	notificationRepo "cool-games/internal/notification/repository"
+	notificationUcase "cool-games/internal/notification/usecase"
+
+	mediaDelivery "cool-games/internal/media/delivery"
+	mediaRepo "cool-games/internal/media/repository"
+	"cool-games/internal/media/storage"
+	mediaUcase "cool-games/internal/media/usecase"
+
+	bundleRepo "cool-games/internal/bundle/repository"
+	bundleUcase "cool-games/internal/bundle/usecase"
+
+	pricingRepo "cool-games/internal/pricing/repository"
+	pricingUcase "cool-games/internal/pricing/usecase"
+
+	tagRepo "cool-games/internal/tag/repository"
+	tagUcase "cool-games/internal/tag/usecase"
+
+	wishlistRepo "cool-games/internal/wishlist/repository"
+	wishlistUcase "cool-games/internal/wishlist/usecase"
+
+	healthDelivery "cool-games/internal/health/delivery"
+
+	"cool-games/internal/openapi"
+	openapiDelivery "cool-games/internal/openapi/delivery"
+
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
-	db := config.ConnectDB()
-    if err := godotenv.Load(); err != nil {
-        fmt.Println("Warning: .env file not found, using system environment")
-    }
+	// Log as JSON from the start; the configured level applies once loaded.
+	logLevel := new(slog.LevelVar)
+	slog.SetDefault(logging.New(os.Stdout, logLevel))
+
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("Failed to load configuration", err)
+	}
+	logLevel.Set(cfg.Log.SlogLevel())
+	if cfg.Production() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Env)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
 
-    jwtSecret := os.Getenv("JWT_SECRET")
-    if jwtSecret == "" {
-        jwtSecret = "default_secret_for_dev_only"
-    }
-    
-    fmt.Printf("LOG: JWT Secret loaded. Length: %d characters\n", len(jwtSecret))
+	db, err := config.ConnectDB(cfg.DB)
+	if err != nil {
+		fatal("Failed to connect to database", err)
+	}
+	slog.Info("Connected to database", "env", cfg.Env)
 
-	r := gin.Default()
+    jwtSecret := cfg.Auth.JWTSecret
+
+	// AUTO_MIGRATE brings the schema up to date before serving; otherwise run
+	// cmd/migrate as a deploy step.
+	if cfg.AutoMigrate {
+		m, err := migrations.New(db)
+		if err != nil {
+			fatal("Failed to load migrations", err)
+		}
+		applied, err := m.Up(context.Background())
+		if err != nil {
+			fatal("Failed to migrate database", err)
+		}
+		slog.Info("Database migrated", "applied", len(applied), "version", m.Latest())
+	}
+
+	// ctx ends on SIGINT or SIGTERM; workers run until the server has drained.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	appMetrics := metrics.New()
+	appMetrics.WatchDB(cfg.DB.Name, db)
+
+	r := gin.New()
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithGinFilter(middleware.TraceFilter)))
+	r.Use(middleware.RequestID(), middleware.RequestLogger(), middleware.Metrics(appMetrics), middleware.Recovery(), middleware.ErrorHandler())
+	r.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+
+	health := healthDelivery.NewHealthHandler(r, db)
+
+	nRepo := notificationRepo.NewPsqlNotificationRepository(db)
+	nUcase := notificationUcase.NewNotificationUsecase(nRepo, cfg.Timeouts.Notification)
+
+	var notif domain.Notifier = nUcase
+	if path := cfg.Notifications.File; path != "" {
+		fileNotif, err := notifier.NewFileNotifier(path)
+		if err != nil {
+			fatal("Failed to open notification file", err)
+		}
+		notif = notifier.NewMultiNotifier(nUcase, fileNotif)
+	}
 
 	uRepo := authRepo.NewPsqlUserRepository(db)
 	cRepo := authRepo.NewPsqlCustomerRepository(db)
 	
-	aUcase := authUcase.NewAuthUsecase(uRepo, cRepo, jwtSecret, 5*time.Second)
-	custUcase := authUcase.NewCustomerUsecase(cRepo, 5*time.Second)
+	gRepo := gameRepo.NewPsqlGameRepository(db)
+
+	prRepo := pricingRepo.NewPsqlPricingRepository(db)
+	prUcase := pricingUcase.NewPricingUsecase(prRepo, gRepo, cRepo, cfg.Pricing.BaseCurrency, cfg.Timeouts.Pricing)
 	
-	authDelivery.NewAuthHandler(r, aUcase)
-	authDelivery.NewCustomerHandler(r, custUcase, jwtSecret)
+	aUcase := authUcase.NewAuthUsecase(uRepo, cRepo, jwtSecret, appMetrics, cfg.Timeouts.Auth)
+	custUcase := authUcase.NewCustomerUsecase(cRepo, prUcase, cfg.Timeouts.Auth)
 
-	gRepo := gameRepo.NewPsqlGameRepository(db)
-	gUcase := gameUcase.NewGameUsecase(gRepo, 5*time.Second)
-	gameDelivery.NewGameHandler(r, gUcase, jwtSecret)
+	blobs, err := storage.NewLocalBlobStore(cfg.Media.Dir, "/media")
+	if err != nil {
+		fatal("Failed to prepare media directory", err)
+	}
+	gUcase := gameUcase.NewGameUsecase(gRepo, prUcase, blobs, cfg.Timeouts.Game)
+
+	mRepo := mediaRepo.NewPsqlMediaRepository(db)
+	mUcase := mediaUcase.NewMediaUsecase(mRepo, gRepo, blobs, cfg.Timeouts.Media)
+	mediaDelivery.NewMediaFileHandler(r, blobs)
+
+	catRepo := gameRepo.NewPsqlCatalogRepository(db)
+	catUcase := gameUcase.NewCatalogUsecase(catRepo, cfg.Timeouts.Catalog)
+
+	workers.Go(func() {
+		gameUcase.RunTrashPurger(workerCtx, gUcase, time.Duration(cfg.Games.TrashRetentionDays)*24*time.Hour, time.Hour)
+	})
 
 	oRepo := orderRepo.NewPsqlOrderRepository(db)
     lRepo := orderRepo.NewPsqlLibraryRepository(db)
-	oUcase := orderUcase.NewOrderUsecase(gRepo, cRepo, oRepo, lRepo, 10*time.Second) 
-    orderDelivery.NewOrderHandler(r, oUcase, jwtSecret)
+	stockMonitor := gameUcase.NewStockMonitor(gRepo, notif, cfg.Timeouts.Game)
+	workers.Go(func() {
+		stockMonitor.Run(workerCtx)
+	})
+	bRepo := bundleRepo.NewPsqlBundleRepository(db)
+	oUcase := orderUcase.NewOrderUsecase(gRepo, cRepo, oRepo, lRepo, bRepo, notif, stockMonitor, prUcase, appMetrics, cfg.Timeouts.Order)
+
+	bUcase := bundleUcase.NewBundleUsecase(bRepo, gRepo, lRepo, prUcase, cfg.Timeouts.Bundle)
 
 	genreRepo := genreRepo.NewPsqlGenreRepository(db)
-	genreUcase := genreUcase.NewGenreUsecase(genreRepo, 5*time.Second)
-	genreDelivery.NewGenreHandler(r, genreUcase, jwtSecret)
+	genreUcase := genreUcase.NewGenreUsecase(genreRepo, cfg.Timeouts.Genre)
+
+	tRepo := tagRepo.NewPsqlTagRepository(db)
+	tUcase := tagUcase.NewTagUsecase(tRepo, gRepo, cfg.Timeouts.Tag)
+
+	wRepo := wishlistRepo.NewPsqlWishlistRepository(db)
+	wUcase := wishlistUcase.NewWishlistUsecase(wRepo, gRepo, notif, prUcase, cfg.Timeouts.Wishlist)
+	workers.Go(func() {
+		wishlistUcase.RunWishlistWatcher(workerCtx, wUcase, time.Minute)
+	})
+
+	a := &api{
+		jwtSecret:    jwtSecret,
+		auth:         aUcase,
+		customer:     custUcase,
+		game:         gUcase,
+		catalog:      catUcase,
+		media:        mUcase,
+		order:        oUcase,
+		bundle:       bUcase,
+		pricing:      prUcase,
+		genre:        genreUcase,
+		tag:          tUcase,
+		wishlist:     wUcase,
+		notification: nUcase,
+	}
+	a.mount(r, cfg.API)
+
+	spec, err := openapi.JSON()
+	if err != nil {
+		fatal("Failed to load the OpenAPI document", err)
+	}
+	openapiDelivery.NewOpenAPIHandler(r, spec)
+
+	// A route added without documentation stops the server in development;
+	// production only complains, so a docs slip cannot take the API down.
+	if err := openapi.Check(r.Routes(), "/v1"); err != nil {
+		if !cfg.Production() {
+			fatal("OpenAPI document is out of date", err)
+		}
+		slog.Error("OpenAPI document is out of date", "err", err)
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+	// Shutdown waits for idle connections, which a notification stream never
+	// is; closing the subscribers ends them.
+	srv.RegisterOnShutdown(nUcase.CloseSubscribers)
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Server failed", err)
+		}
+	}()
+	slog.Info("Listening", "addr", srv.Addr)
+
+	<-ctx.Done()
+	stop()
+
+	// Fail /readyz first and give the load balancer time to notice, then stop
+	// accepting connections and let in-flight requests such as purchases
+	// finish before the database goes away.
+	health.Drain()
+	slog.Info("Shutting down, waiting for the load balancer", "delay", cfg.HTTP.ShutdownDrainDelay.String())
+	time.Sleep(cfg.HTTP.ShutdownDrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Requests still running at shutdown timeout", "err", err)
+		srv.Close()
+	}
+
+	stopWorkers()
+	workers.Wait()
+
+	if err := db.Close(); err != nil {
+		slog.Error("Failed to close database", "err", err)
+	}
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "err", err)
+	}
+	slog.Info("Server stopped")
+}
 
-	r.Run(":8080")
-}
\ No newline at end of file
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"cool-games/config"
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+
+	authDelivery "cool-games/internal/auth/delivery"
+	bundleDelivery "cool-games/internal/bundle/delivery"
+	gameDelivery "cool-games/internal/game/delivery"
+	genreDelivery "cool-games/internal/genre/delivery"
+	mediaDelivery "cool-games/internal/media/delivery"
+	notificationDelivery "cool-games/internal/notification/delivery"
+	orderDelivery "cool-games/internal/order/delivery"
+	pricingDelivery "cool-games/internal/pricing/delivery"
+	tagDelivery "cool-games/internal/tag/delivery"
+	wishlistDelivery "cool-games/internal/wishlist/delivery"
+
+	"github.com/gin-gonic/gin"
+)
+
+// api holds the usecases the versioned handlers are built on. Every version
+// mounts its own handlers over the same usecases.
+type api struct {
+	jwtSecret string
+
+	auth         domain.AuthUsecase
+	customer     domain.CustomerUsecase
+	game         domain.GameUsecase
+	catalog      domain.CatalogUsecase
+	media        domain.MediaUsecase
+	order        domain.OrderUsecase
+	bundle       domain.BundleUsecase
+	pricing      domain.PricingUsecase
+	genre        domain.GenreUsecase
+	tag          domain.TagUsecase
+	wishlist     domain.WishlistUsecase
+	notification domain.NotificationUsecase
+}
+
+// apiVersion is one generation of the API, served under /<name>.
+type apiVersion struct {
+	name  string
+	mount func(gin.IRouter)
+}
+
+// versions lists the API generations served side by side. A v2 gets its own
+// entry whose mount registers the handlers it changes and calls the v1
+// constructors for everything else; v1 keeps serving unchanged until it is
+// removed from this list.
+func (a *api) versions() []apiVersion {
+	return []apiVersion{
+		{name: "v1", mount: a.v1},
+	}
+}
+
+// mount registers every version under /<name>. The unversioned paths clients
+// used before /v1 serve v1 until the sunset.
+func (a *api) mount(r gin.IRouter, cfg config.APIConfig) {
+	for _, v := range a.versions() {
+		v.mount(r.Group("/" + v.name))
+	}
+	if cfg.LegacyRoutes {
+		a.v1(r.Group("", middleware.Deprecated(cfg.LegacyDeprecatedAt(), cfg.LegacySunsetAt(), "/v1")))
+	}
+}
+
+func (a *api) v1(r gin.IRouter) {
+	authDelivery.NewAuthHandler(r, a.auth)
+	authDelivery.NewCustomerHandler(r, a.customer, a.jwtSecret)
+	gameDelivery.NewGameHandler(r, a.game, a.jwtSecret)
+	gameDelivery.NewCatalogHandler(r, a.catalog, a.jwtSecret)
+	mediaDelivery.NewMediaHandler(r, a.media, a.jwtSecret)
+	orderDelivery.NewOrderHandler(r, a.order, a.jwtSecret)
+	bundleDelivery.NewBundleHandler(r, a.bundle, a.jwtSecret)
+	pricingDelivery.NewPricingHandler(r, a.pricing, a.jwtSecret)
+	genreDelivery.NewGenreHandler(r, a.genre, a.jwtSecret)
+	tagDelivery.NewTagHandler(r, a.tag, a.jwtSecret)
+	wishlistDelivery.NewWishlistHandler(r, a.wishlist, a.jwtSecret)
+	notificationDelivery.NewNotificationHandler(r, a.notification, a.jwtSecret)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+
+	"cool-games/config"
+	healthDelivery "cool-games/internal/health/delivery"
+	mediaDelivery "cool-games/internal/media/delivery"
+	"cool-games/internal/openapi"
+	openapiDelivery "cool-games/internal/openapi/delivery"
+
+	"github.com/gin-gonic/gin"
+)
+
+// router registers the same routes as main, on usecases that are never
+// called.
+func router(t *testing.T, cfg config.APIConfig) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/metrics", func(*gin.Context) {})
+	healthDelivery.NewHealthHandler(r, nil)
+	mediaDelivery.NewMediaFileHandler(r, nil)
+
+	a := &api{jwtSecret: "test-secret"}
+	a.mount(r, cfg)
+
+	spec, err := openapi.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	openapiDelivery.NewOpenAPIHandler(r, spec)
+	return r
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	withLegacy := config.Default().API
+	withoutLegacy := withLegacy
+	withoutLegacy.LegacyRoutes = false
+
+	tests := []struct {
+		name string
+		cfg  config.APIConfig
+	}{
+		{"v1 and legacy aliases", withLegacy},
+		{"v1 only", withoutLegacy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := router(t, tt.cfg)
+			if err := openapi.Check(r.Routes(), "/v1"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestOpenAPIReportsUndocumentedRoute(t *testing.T) {
+	r := router(t, config.Default().API)
+	r.GET("/v1/undocumented/:id", func(*gin.Context) {})
+
+	if err := openapi.Check(r.Routes(), "/v1"); err == nil {
+		t.Fatal("Check accepted a route missing from openapi.yaml")
+	}
+}
@@ -0,0 +1,111 @@
+// Command migrate manages the database schema.
+//
+//	migrate up            apply every pending migration
+//	migrate down [N]      revert the last N migrations (default 1)
+//	migrate to VERSION    migrate up or down to VERSION (0 reverts everything)
+//	migrate status        list migrations and when they were applied
+//	migrate force VERSION mark migrations up to VERSION as applied without
+//	                      running them, to adopt an existing database
+package main
+
+import (
+	"context"
+	"cool-games/config"
+	"cool-games/migrations"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := config.ConnectDB(cfg.DB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := migrations.New(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "up":
+		report(m.Up(ctx))
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps = parseArg(os.Args[2])
+		}
+		report(m.Down(ctx, steps))
+	case "to":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		report(m.To(ctx, int64(parseArg(os.Args[2]))))
+	case "force":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		if err := m.Force(ctx, int64(parseArg(os.Args[2]))); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("schema_migrations updated")
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			if s.Changed {
+				applied += " (file changed since)"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		w.Flush()
+	default:
+		usage()
+	}
+}
+
+func report(done []migrations.Migration, err error) {
+	for _, mig := range done {
+		fmt.Printf("%04d_%s\n", mig.Version, mig.Name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(done) == 0 {
+		fmt.Println("nothing to do")
+	}
+}
+
+func parseArg(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		log.Fatalf("%q is not a valid number", s)
+	}
+	return n
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | down [N] | to VERSION | status | force VERSION")
+	os.Exit(2)
+}
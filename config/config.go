@@ -0,0 +1,393 @@
+// Package config loads the settings for the binaries under cmd/. Values
+// come from, in increasing order of precedence: the defaults below, the
+// YAML or TOML file named by CONFIG_FILE, a .env file in the working
+// directory, and the process environment.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+
+	// devJWTSecret lets a fresh checkout run without configuration. It is
+	// refused in production.
+	devJWTSecret = "default_secret_for_dev_only"
+
+	minProductionSecretLen = 32
+)
+
+type Config struct {
+	Env         string `yaml:"env"`
+	AutoMigrate bool   `yaml:"auto_migrate"`
+
+	Log           LogConfig          `yaml:"log"`
+	Tracing       TracingConfig      `yaml:"tracing"`
+	HTTP          HTTPConfig         `yaml:"http"`
+	API           APIConfig          `yaml:"api"`
+	DB            DBConfig           `yaml:"db"`
+	Auth          AuthConfig         `yaml:"auth"`
+	Media         MediaConfig        `yaml:"media"`
+	Pricing       PricingConfig      `yaml:"pricing"`
+	Notifications NotificationConfig `yaml:"notifications"`
+	Games         GamesConfig        `yaml:"games"`
+	Timeouts      Timeouts           `yaml:"timeouts"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error.
+	Level string `yaml:"level"`
+}
+
+// SlogLevel is Level parsed; Validate has already rejected anything else.
+func (c LogConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.Level))
+	return level
+}
+
+const (
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingOTLP   = "otlp"
+)
+
+type TracingConfig struct {
+	// Exporter is none, stdout (pretty-printed spans on stderr) or otlp
+	// (OTLP over HTTP).
+	Exporter string `yaml:"exporter"`
+	// OTLPEndpoint is a URL such as http://localhost:4318 for a local
+	// collector. When empty the OTEL_EXPORTER_OTLP_* variables apply.
+	OTLPEndpoint string  `yaml:"otlp_endpoint"`
+	SampleRatio  float64 `yaml:"sample_ratio"`
+	ServiceName  string  `yaml:"service_name"`
+}
+
+type HTTPConfig struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// ShutdownDrainDelay is how long /readyz fails before the listener
+	// closes, so the load balancer stops routing here first.
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay"`
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout"`
+}
+
+// APIConfig covers the unversioned paths that predate /v1. They still serve
+// the v1 handlers but announce their removal.
+type APIConfig struct {
+	LegacyRoutes bool `yaml:"legacy_routes"`
+	// LegacyDeprecated and LegacySunset are YYYY-MM-DD dates, sent in the
+	// Deprecation and Sunset headers.
+	LegacyDeprecated string `yaml:"legacy_deprecated"`
+	LegacySunset     string `yaml:"legacy_sunset"`
+}
+
+// LegacyDeprecatedAt is LegacyDeprecated parsed; Validate has already
+// rejected anything else.
+func (c APIConfig) LegacyDeprecatedAt() time.Time {
+	t, _ := time.Parse(time.DateOnly, c.LegacyDeprecated)
+	return t
+}
+
+func (c APIConfig) LegacySunsetAt() time.Time {
+	t, _ := time.Parse(time.DateOnly, c.LegacySunset)
+	return t
+}
+
+type DBConfig struct {
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Name     string `yaml:"name"`
+
+	// SSLMode is passed to lib/pq: disable, require, verify-ca or
+	// verify-full. The verify modes check the server against SSLRootCert.
+	SSLMode     string `yaml:"sslmode"`
+	SSLRootCert string `yaml:"sslrootcert"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+}
+
+type AuthConfig struct {
+	JWTSecret string `yaml:"jwt_secret"`
+}
+
+type MediaConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+type PricingConfig struct {
+	BaseCurrency string `yaml:"base_currency"`
+}
+
+type NotificationConfig struct {
+	// File, when set, also appends every notification to it as JSON lines.
+	File string `yaml:"file"`
+}
+
+type GamesConfig struct {
+	TrashRetentionDays int `yaml:"trash_retention_days"`
+}
+
+// Timeouts bounds each usecase call, per module.
+type Timeouts struct {
+	Auth         time.Duration `yaml:"auth"`
+	Game         time.Duration `yaml:"game"`
+	Catalog      time.Duration `yaml:"catalog"`
+	Media        time.Duration `yaml:"media"`
+	Order        time.Duration `yaml:"order"`
+	Bundle       time.Duration `yaml:"bundle"`
+	Pricing      time.Duration `yaml:"pricing"`
+	Genre        time.Duration `yaml:"genre"`
+	Tag          time.Duration `yaml:"tag"`
+	Wishlist     time.Duration `yaml:"wishlist"`
+	Notification time.Duration `yaml:"notification"`
+}
+
+// Default is a configuration that runs against a local database.
+func Default() *Config {
+	return &Config{
+		Env: EnvDevelopment,
+		Log: LogConfig{Level: "info"},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			SampleRatio: 1,
+			ServiceName: "cool-games",
+		},
+		HTTP: HTTPConfig{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       60 * time.Second,
+			// Covers the slowest usecase, catalog export; the notification
+			// stream lifts it for itself.
+			WriteTimeout:       75 * time.Second,
+			IdleTimeout:        120 * time.Second,
+			ShutdownDrainDelay: 5 * time.Second,
+			ShutdownTimeout:    30 * time.Second,
+		},
+		API: APIConfig{
+			LegacyRoutes:     true,
+			LegacyDeprecated: "2026-10-19",
+			LegacySunset:     "2027-04-30",
+		},
+		DB: DBConfig{
+			Port:            5432,
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Auth:    AuthConfig{JWTSecret: devJWTSecret},
+		Media:   MediaConfig{Dir: "./uploads"},
+		Pricing: PricingConfig{BaseCurrency: "USD"},
+		Games:   GamesConfig{TrashRetentionDays: 30},
+		Timeouts: Timeouts{
+			Auth:         5 * time.Second,
+			Game:         5 * time.Second,
+			Catalog:      60 * time.Second,
+			Media:        30 * time.Second,
+			Order:        10 * time.Second,
+			Bundle:       10 * time.Second,
+			Pricing:      5 * time.Second,
+			Genre:        5 * time.Second,
+			Tag:          5 * time.Second,
+			Wishlist:     5 * time.Second,
+			Notification: 5 * time.Second,
+		},
+	}
+}
+
+// Load builds the configuration and validates it.
+func Load() (*Config, error) {
+	if _, err := os.Stat(".env"); err == nil {
+		if err := godotenv.Load(); err != nil {
+			return nil, fmt.Errorf(".env: %w", err)
+		}
+	}
+
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := decodeFile(path, cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeFile reads the file at path into cfg, as YAML or TOML depending on
+// its extension. Unknown keys are an error, so a misspelt setting does not
+// silently keep its default.
+func decodeFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+	case ".toml":
+		// go-toml only decodes durations from nanosecond integers. The
+		// document goes on as JSON, which is valid YAML, so both formats
+		// share the yaml field names and write durations as "15s".
+		var doc map[string]interface{}
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported config file extension %q, use .yaml, .yml or .toml", ext)
+	}
+	return yaml.UnmarshalWithOptions(data, cfg, yaml.Strict())
+}
+
+func (c *Config) Production() bool {
+	return c.Env == EnvProduction
+}
+
+// Validate reports every problem at once rather than the first.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		fail("APP_ENV must be %s or %s, got %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		fail("LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout, TracingOTLP:
+	default:
+		fail("TRACING_EXPORTER must be %s, %s or %s, got %q", TracingNone, TracingStdout, TracingOTLP, c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.Tracing.ServiceName == "" {
+		fail("TRACING_SERVICE_NAME is required")
+	}
+
+	if c.HTTP.Addr == "" {
+		fail("HTTP_ADDR is required")
+	}
+
+	if c.API.LegacyRoutes {
+		deprecated, errDeprecated := time.Parse(time.DateOnly, c.API.LegacyDeprecated)
+		if errDeprecated != nil {
+			fail("API_LEGACY_DEPRECATED must be a date such as 2026-10-19, got %q", c.API.LegacyDeprecated)
+		}
+		sunset, errSunset := time.Parse(time.DateOnly, c.API.LegacySunset)
+		if errSunset != nil {
+			fail("API_LEGACY_SUNSET must be a date such as 2027-04-30, got %q", c.API.LegacySunset)
+		}
+		if errDeprecated == nil && errSunset == nil && !sunset.After(deprecated) {
+			fail("API_LEGACY_SUNSET must be after API_LEGACY_DEPRECATED")
+		}
+	}
+
+	if c.DB.User == "" || c.DB.Password == "" || c.DB.Host == "" || c.DB.Name == "" {
+		fail("DB_USER, DB_PASSWORD, DB_HOST and DB_NAME are required")
+	}
+	if c.DB.Port <= 0 || c.DB.Port > 65535 {
+		fail("DB_PORT must be between 1 and 65535")
+	}
+	switch c.DB.SSLMode {
+	case "disable", "require":
+	case "verify-ca", "verify-full":
+		if c.DB.SSLRootCert == "" {
+			fail("DB_SSLROOTCERT is required with DB_SSLMODE=%s", c.DB.SSLMode)
+		}
+	default:
+		fail("DB_SSLMODE must be disable, require, verify-ca or verify-full, got %q", c.DB.SSLMode)
+	}
+	if c.DB.MaxOpenConns < 0 || c.DB.MaxIdleConns < 0 {
+		fail("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS cannot be negative")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		fail("DB_MAX_IDLE_CONNS cannot exceed DB_MAX_OPEN_CONNS")
+	}
+
+	if c.Auth.JWTSecret == "" {
+		fail("JWT_SECRET is required")
+	}
+	if c.Production() {
+		if c.Auth.JWTSecret == devJWTSecret {
+			fail("JWT_SECRET must be set in production; the development default is public")
+		} else if len(c.Auth.JWTSecret) < minProductionSecretLen {
+			fail("JWT_SECRET must be at least %d characters in production", minProductionSecretLen)
+		}
+	}
+
+	if len(c.Pricing.BaseCurrency) != 3 || strings.ToUpper(c.Pricing.BaseCurrency) != c.Pricing.BaseCurrency {
+		fail("BASE_CURRENCY must be a three-letter ISO 4217 code such as USD")
+	}
+	if c.Media.Dir == "" {
+		fail("MEDIA_DIR is required")
+	}
+	if c.Games.TrashRetentionDays <= 0 {
+		fail("GAME_TRASH_RETENTION_DAYS must be positive")
+	}
+
+	for _, t := range c.Timeouts.byEnv() {
+		if *t.dst <= 0 {
+			fail("%s must be positive", t.key)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// byEnv lists each timeout with the variable that overrides it.
+func (t *Timeouts) byEnv() []durationVar {
+	return []durationVar{
+		{"AUTH_TIMEOUT", &t.Auth},
+		{"GAME_TIMEOUT", &t.Game},
+		{"CATALOG_TIMEOUT", &t.Catalog},
+		{"MEDIA_TIMEOUT", &t.Media},
+		{"ORDER_TIMEOUT", &t.Order},
+		{"BUNDLE_TIMEOUT", &t.Bundle},
+		{"PRICING_TIMEOUT", &t.Pricing},
+		{"GENRE_TIMEOUT", &t.Genre},
+		{"TAG_TIMEOUT", &t.Tag},
+		{"WISHLIST_TIMEOUT", &t.Wishlist},
+		{"NOTIFICATION_TIMEOUT", &t.Notification},
+	}
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// valid is the default configuration with the database settings a real
+// deployment has to provide.
+func valid() *Config {
+	cfg := Default()
+	cfg.DB.User = "games"
+	cfg.DB.Password = "secret"
+	cfg.DB.Host = "localhost"
+	cfg.DB.Name = "cool_games"
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	production := func(c *Config) {
+		c.Env = EnvProduction
+		c.Auth.JWTSecret = strings.Repeat("s", minProductionSecretLen)
+	}
+
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr []string
+	}{
+		{
+			name:   "defaults",
+			change: func(c *Config) {},
+		},
+		{
+			name:   "development accepts the development secret",
+			change: func(c *Config) { c.Auth.JWTSecret = devJWTSecret },
+		},
+		{
+			name:   "production with its own secret",
+			change: production,
+		},
+		{
+			name: "production refuses the development secret",
+			change: func(c *Config) {
+				production(c)
+				c.Auth.JWTSecret = devJWTSecret
+			},
+			wantErr: []string{"the development default is public"},
+		},
+		{
+			name: "production refuses a short secret",
+			change: func(c *Config) {
+				production(c)
+				c.Auth.JWTSecret = "short"
+			},
+			wantErr: []string{"at least 32 characters"},
+		},
+		{
+			name:    "unknown environment",
+			change:  func(c *Config) { c.Env = "staging" },
+			wantErr: []string{"APP_ENV"},
+		},
+		{
+			name:    "missing database settings",
+			change:  func(c *Config) { c.DB.Password = "" },
+			wantErr: []string{"DB_USER, DB_PASSWORD"},
+		},
+		{
+			name:    "verify-full without a CA",
+			change:  func(c *Config) { c.DB.SSLMode = "verify-full" },
+			wantErr: []string{"DB_SSLROOTCERT"},
+		},
+		{
+			name:    "sunset before deprecation",
+			change:  func(c *Config) { c.API.LegacySunset = "2026-01-01" },
+			wantErr: []string{"API_LEGACY_SUNSET must be after"},
+		},
+		{
+			name: "every problem is reported",
+			change: func(c *Config) {
+				c.Log.Level = "loud"
+				c.Pricing.BaseCurrency = "usd"
+				c.Timeouts.Order = 0
+			},
+			wantErr: []string{"LOG_LEVEL", "BASE_CURRENCY", "ORDER_TIMEOUT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.change(cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("configuration accepted")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	const yamlConfig = `
+env: production
+auth:
+  jwt_secret: 0123456789abcdef0123456789abcdef
+http:
+  write_timeout: 90s
+db:
+  user: games
+  password: secret
+  host: db.internal
+  name: cool_games
+timeouts:
+  order: 15s
+`
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "yaml",
+			file:    "config.yaml",
+			content: yamlConfig,
+		},
+		{
+			name:    "yml",
+			file:    "config.yml",
+			content: yamlConfig,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `
+env = "production"
+
+[auth]
+jwt_secret = "0123456789abcdef0123456789abcdef"
+
+[http]
+write_timeout = "90s"
+
+[db]
+user = "games"
+password = "secret"
+host = "db.internal"
+name = "cool_games"
+
+[timeouts]
+order = "15s"
+`,
+		},
+		{
+			name:    "unknown yaml key",
+			file:    "config.yaml",
+			content: "http:\n  write_timout: 90s\n",
+			wantErr: "write_timout",
+		},
+		{
+			name:    "unknown toml key",
+			file:    "config.toml",
+			content: "[http]\nwrite_timout = \"90s\"\n",
+			wantErr: "write_timout",
+		},
+		{
+			name:    "unsupported extension",
+			file:    "config.json",
+			content: "{}",
+			wantErr: `unsupported config file extension ".json"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := Default()
+			err := decodeFile(path, cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("decodeFile() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeFile(): %v", err)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate(): %v", err)
+			}
+			if cfg.Env != EnvProduction || cfg.DB.Host != "db.internal" {
+				t.Errorf("env = %q, db host = %q", cfg.Env, cfg.DB.Host)
+			}
+			if cfg.HTTP.WriteTimeout != 90*time.Second || cfg.Timeouts.Order != 15*time.Second {
+				t.Errorf("write timeout = %v, order timeout = %v", cfg.HTTP.WriteTimeout, cfg.Timeouts.Order)
+			}
+			// Settings the file leaves out keep their defaults.
+			if cfg.HTTP.Addr != Default().HTTP.Addr || cfg.Timeouts.Auth != Default().Timeouts.Auth {
+				t.Errorf("addr = %q, auth timeout = %v, want the defaults", cfg.HTTP.Addr, cfg.Timeouts.Auth)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+type durationVar struct {
+	key string
+	dst *time.Duration
+}
+
+// applyEnv overrides c with every variable that is set. A set but
+// unparsable value is an error rather than a silent fallback.
+func (c *Config) applyEnv() error {
+	var errs []error
+	str := func(key string, dst *string) {
+		if v, ok := os.LookupEnv(key); ok {
+			*dst = v
+		}
+	}
+	num := func(key string, dst *int) {
+		if v, ok := os.LookupEnv(key); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", key, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	flag := func(key string, dst *bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a boolean", key, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	ratio := func(key string, dst *float64) {
+		if v, ok := os.LookupEnv(key); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", key, v))
+				return
+			}
+			*dst = f
+		}
+	}
+	dur := func(key string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(key); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration such as 10s", key, v))
+				return
+			}
+			*dst = d
+		}
+	}
+
+	str("APP_ENV", &c.Env)
+	flag("AUTO_MIGRATE", &c.AutoMigrate)
+	str("LOG_LEVEL", &c.Log.Level)
+	str("TRACING_EXPORTER", &c.Tracing.Exporter)
+	str("TRACING_OTLP_ENDPOINT", &c.Tracing.OTLPEndpoint)
+	ratio("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+	str("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+
+	str("HTTP_ADDR", &c.HTTP.Addr)
+	dur("HTTP_READ_HEADER_TIMEOUT", &c.HTTP.ReadHeaderTimeout)
+	dur("HTTP_READ_TIMEOUT", &c.HTTP.ReadTimeout)
+	dur("HTTP_WRITE_TIMEOUT", &c.HTTP.WriteTimeout)
+	dur("HTTP_IDLE_TIMEOUT", &c.HTTP.IdleTimeout)
+	dur("SHUTDOWN_DRAIN_DELAY", &c.HTTP.ShutdownDrainDelay)
+	dur("SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout)
+
+	flag("API_LEGACY_ROUTES", &c.API.LegacyRoutes)
+	str("API_LEGACY_DEPRECATED", &c.API.LegacyDeprecated)
+	str("API_LEGACY_SUNSET", &c.API.LegacySunset)
+
+	str("DB_USER", &c.DB.User)
+	str("DB_PASSWORD", &c.DB.Password)
+	str("DB_HOST", &c.DB.Host)
+	num("DB_PORT", &c.DB.Port)
+	str("DB_NAME", &c.DB.Name)
+	str("DB_SSLMODE", &c.DB.SSLMode)
+	str("DB_SSLROOTCERT", &c.DB.SSLRootCert)
+	num("DB_MAX_OPEN_CONNS", &c.DB.MaxOpenConns)
+	num("DB_MAX_IDLE_CONNS", &c.DB.MaxIdleConns)
+	dur("DB_CONN_MAX_LIFETIME", &c.DB.ConnMaxLifetime)
+	dur("DB_CONN_MAX_IDLE_TIME", &c.DB.ConnMaxIdleTime)
+
+	str("JWT_SECRET", &c.Auth.JWTSecret)
+	str("MEDIA_DIR", &c.Media.Dir)
+	str("BASE_CURRENCY", &c.Pricing.BaseCurrency)
+	str("NOTIFICATION_FILE", &c.Notifications.File)
+	num("GAME_TRASH_RETENTION_DAYS", &c.Games.TrashRetentionDays)
+
+	for _, t := range c.Timeouts.byEnv() {
+		dur(t.key, t.dst)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid environment: %w", errors.Join(errs...))
+	}
+	return nil
+}
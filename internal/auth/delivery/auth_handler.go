@@ -11,7 +11,7 @@ type AuthHandler struct {
 	AuthUsecase domain.AuthUsecase
 }
 
-func NewAuthHandler(r *gin.Engine, au domain.AuthUsecase) {
+func NewAuthHandler(r gin.IRouter, au domain.AuthUsecase) {
 	handler := &AuthHandler{
 		AuthUsecase: au,
 	}
@@ -23,13 +23,13 @@ func NewAuthHandler(r *gin.Engine, au domain.AuthUsecase) {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var user domain.User
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(domain.ErrInvalidBody.Wrap(err))
 		return
 	}
 
 	res, err := h.AuthUsecase.Register(c.Request.Context(), &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -39,13 +39,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req domain.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(domain.ErrInvalidBody.Wrap(err))
 		return
 	}
 
 	res, err := h.AuthUsecase.Login(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+		c.Error(err)
 		return
 	}
 
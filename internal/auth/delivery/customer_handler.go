@@ -11,7 +11,7 @@ type CustomerHandler struct {
 	CustomerUsecase domain.CustomerUsecase
 }
 
-func NewCustomerHandler(r *gin.Engine, cu domain.CustomerUsecase, jwtSecret string) {
+func NewCustomerHandler(r gin.IRouter, cu domain.CustomerUsecase, jwtSecret string) {
 	handler := &CustomerHandler{CustomerUsecase: cu}
 
 	customerGroup := r.Group("/me")
@@ -19,6 +19,7 @@ func NewCustomerHandler(r *gin.Engine, cu domain.CustomerUsecase, jwtSecret stri
 	customerGroup.Use(middleware.RoleBlock("customer"))
 	{
 		customerGroup.GET("/profile", handler.GetProfile)
+		customerGroup.PUT("/region", handler.UpdateRegion)
 	}
 }
 
@@ -26,8 +27,24 @@ func (h *CustomerHandler) GetProfile(c *gin.Context) {
 	userID := c.MustGet("user_id").(int)
 	profile, err := h.CustomerUsecase.GetProfile(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "profile not found"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, profile)
-}
\ No newline at end of file
+}
+
+func (h *CustomerHandler) UpdateRegion(c *gin.Context) {
+	var req domain.RegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+
+	userID := c.MustGet("user_id").(int)
+	profile, err := h.CustomerUsecase.UpdateRegion(c.Request.Context(), userID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
@@ -21,9 +21,12 @@ func (m *psqlCustomerRepository) Create(ctx context.Context, c *domain.Customer)
 }
 
 func (m *psqlCustomerRepository) GetByUserID(ctx context.Context, userID int) (domain.Customer, error) {
-	query := `SELECT id, user_id, customer_name, current_balance, created_at FROM customers WHERE user_id = $1`
+	query := `SELECT id, user_id, customer_name, current_balance, region, COALESCE(currency, ''), created_at FROM customers WHERE user_id = $1`
 	var c domain.Customer
-	err := m.db.QueryRowContext(ctx, query, userID).Scan(&c.ID, &c.UserID, &c.CustomerName, &c.CurrentBalance, &c.CreatedAt)
+	err := m.db.QueryRowContext(ctx, query, userID).Scan(&c.ID, &c.UserID, &c.CustomerName, &c.CurrentBalance, &c.Region, &c.Currency, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return domain.Customer{}, domain.ErrCustomerNotFound
+	}
 	if err != nil {
 		return domain.Customer{}, err
 	}
@@ -40,7 +43,25 @@ func (m *psqlCustomerRepository) UpdateBalance(ctx context.Context, userID int,
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return domain.ErrGameNotFound
+		return domain.ErrCustomerNotFound
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// UpdateRegion refuses to switch the wallet currency while it holds money, so
+// a balance is never silently reinterpreted in another currency.
+func (m *psqlCustomerRepository) UpdateRegion(ctx context.Context, userID int, region, currency string, currencyChanged bool) error {
+	query := `UPDATE customers SET region = $2, currency = $3, updated_at = NOW()
+              WHERE user_id = $1 AND (current_balance = 0 OR NOT $4)`
+
+	result, err := m.db.ExecContext(ctx, query, userID, region, currency, currencyChanged)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return domain.ErrWalletNotEmpty
+	}
+	return nil
+}
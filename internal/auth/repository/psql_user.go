@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"cool-games/internal/domain"
+
+	"github.com/lib/pq"
 )
 
 type psqlUserRepository struct {
@@ -16,13 +18,20 @@ func NewPsqlUserRepository(db *sql.DB) domain.UserRepository {
 
 func (m *psqlUserRepository) Create(ctx context.Context, u *domain.User) error {
     query := `INSERT INTO users (email, hashed_password, role) VALUES ($1, $2, $3) RETURNING id, created_at`
-    return m.db.QueryRowContext(ctx, query, u.Email, u.HashedPassword, u.Role).Scan(&u.ID, &u.CreatedAt)
+    err := m.db.QueryRowContext(ctx, query, u.Email, u.HashedPassword, u.Role).Scan(&u.ID, &u.CreatedAt)
+    if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+        return domain.ErrEmailTaken
+    }
+    return err
 }
 
 func (m *psqlUserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
 	query := `SELECT id, email, hashed_password, role FROM users WHERE email = $1 AND deleted_at IS NULL`
 	var u domain.User
 	err := m.db.QueryRowContext(ctx, query, email).Scan(&u.ID, &u.Email, &u.HashedPassword, &u.Role)
+	if err == sql.ErrNoRows {
+		return domain.User{}, domain.ErrUserNotFound
+	}
 	return u, err
 }
 
@@ -5,13 +5,17 @@ import (
 	"cool-games/internal/domain"
 	"time"
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var tracer = otel.Tracer("cool-games/internal/auth/usecase")
+
 type authUsecase struct {
     userRepo       domain.UserRepository
     customerRepo   domain.CustomerRepository
     jwtSecret      string
+    metrics        domain.BusinessMetrics
     contextTimeout time.Duration
 }
 
@@ -19,17 +23,21 @@ func NewAuthUsecase(
     repo domain.UserRepository, 
     cRepo domain.CustomerRepository,
     secret string, 
+    m domain.BusinessMetrics,
     timeout time.Duration,
 ) domain.AuthUsecase {
     return &authUsecase{
         userRepo:      repo,
         customerRepo:  cRepo,
         jwtSecret:     secret,
+        metrics:       m,
         contextTimeout: timeout,
     }
 }
 
 func (u *authUsecase) Register(ctx context.Context, user *domain.User) (domain.AuthResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.Register")
+	defer span.End()
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
     if err != nil {
         return domain.AuthResponse{}, err
@@ -53,22 +61,29 @@ case "customer":
             return domain.AuthResponse{}, err
         }
     }
+    u.metrics.UserRegistered(user.Role)
 
     token, _ := u.generateJWT(*user)
     return domain.AuthResponse{Token: token, User: *user}, nil
 }
 
 func (u *authUsecase) Login(ctx context.Context, req domain.LoginRequest) (domain.AuthResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.Login")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
+	// An unknown email and a wrong password look the same to the caller.
 	user, err := u.userRepo.GetByEmail(c, req.Email)
+	if err == domain.ErrUserNotFound {
+		return domain.AuthResponse{}, domain.ErrInvalidCredentials
+	}
 	if err != nil {
 		return domain.AuthResponse{}, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(req.Password)); err != nil {
-		return domain.AuthResponse{}, err
+		return domain.AuthResponse{}, domain.ErrInvalidCredentials
 	}
 
 	token, _ := u.generateJWT(user)
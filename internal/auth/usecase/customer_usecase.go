@@ -3,23 +3,74 @@ package usecase
 import (
 	"context"
 	"cool-games/internal/domain"
+	"regexp"
+	"strings"
 	"time"
 )
 
 type customerUsecase struct {
 	customerRepo   domain.CustomerRepository
+	prices         domain.PriceResolver
 	contextTimeout time.Duration
 }
 
-func NewCustomerUsecase(repo domain.CustomerRepository, timeout time.Duration) domain.CustomerUsecase {
+func NewCustomerUsecase(repo domain.CustomerRepository, prices domain.PriceResolver, timeout time.Duration) domain.CustomerUsecase {
 	return &customerUsecase{
 		customerRepo:   repo,
+		prices:         prices,
 		contextTimeout: timeout,
 	}
 }
 
 func (u *customerUsecase) GetProfile(ctx context.Context, userID int) (domain.Customer, error) {
+    ctx, span := tracer.Start(ctx, "CustomerUsecase.GetProfile")
+    defer span.End()
     c, cancel := context.WithTimeout(ctx, u.contextTimeout)
     defer cancel()
-    return u.customerRepo.GetByUserID(c, userID)
+
+    customer, err := u.customerRepo.GetByUserID(c, userID)
+    if err != nil {
+        return domain.Customer{}, err
+    }
+    if customer.Currency == "" {
+        customer.Currency = u.prices.BaseCurrency()
+    }
+    return customer, nil
+}
+
+var regionCode = regexp.MustCompile(`^[A-Z]{2,3}$`)
+
+// UpdateRegion sets where the customer shops from and the currency their
+// wallet is kept in. The currency needs an exchange rate (or to be the base
+// currency), and can only change while the wallet is empty.
+func (u *customerUsecase) UpdateRegion(ctx context.Context, userID int, req domain.RegionRequest) (domain.Customer, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.UpdateRegion")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	region := strings.ToUpper(req.Region)
+	currency := strings.ToUpper(req.Currency)
+	if !regionCode.MatchString(region) {
+		return domain.Customer{}, domain.ErrInvalidRegion
+	}
+	if err := u.prices.ValidateCurrency(c, currency); err != nil {
+		return domain.Customer{}, err
+	}
+
+	current, err := u.customerRepo.GetByUserID(c, userID)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+	currentCurrency := current.Currency
+	if currentCurrency == "" {
+		currentCurrency = u.prices.BaseCurrency()
+	}
+
+	if err := u.customerRepo.UpdateRegion(c, userID, region, currency, currency != currentCurrency); err != nil {
+		return domain.Customer{}, err
+	}
+	current.Region = region
+	current.Currency = currency
+	return current, nil
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BundleHandler struct {
+	Usecase domain.BundleUsecase
+}
+
+func NewBundleHandler(r gin.IRouter, us domain.BundleUsecase, jwtSecret string) {
+	handler := &BundleHandler{Usecase: us}
+
+	r.GET("/bundles", handler.Fetch)
+	r.GET("/bundles/:id", handler.GetByID)
+
+	protected := r.Group("/bundles")
+	protected.Use(middleware.AuthMiddleware(jwtSecret))
+	{
+		protected.GET("/:id/quote", middleware.RoleBlock("customer"), handler.Quote)
+		protected.POST("", middleware.RoleBlock("publisher"), handler.Create)
+		protected.PUT("/:id", middleware.RoleBlock("publisher"), handler.Update)
+		protected.DELETE("/:id", middleware.RoleBlock("publisher"), handler.Delete)
+	}
+}
+
+func (h *BundleHandler) Fetch(c *gin.Context) {
+	res, err := h.Usecase.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if res == nil {
+		res = []domain.Bundle{}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *BundleHandler) GetByID(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	res, err := h.Usecase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *BundleHandler) Quote(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+
+	res, err := h.Usecase.Quote(c.Request.Context(), id, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *BundleHandler) Create(c *gin.Context) {
+	var req domain.BundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+
+	userID := c.MustGet("user_id").(int)
+	res, err := h.Usecase.Create(c.Request.Context(), req, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *BundleHandler) Update(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req domain.BundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+	res, err := h.Usecase.Update(c.Request.Context(), id, req, userID, role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *BundleHandler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	if err := h.Usecase.Delete(c.Request.Context(), id, userID, role); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type psqlBundleRepository struct {
+	db *sql.DB
+}
+
+func NewPsqlBundleRepository(db *sql.DB) domain.BundleRepository {
+	return &psqlBundleRepository{db: db}
+}
+
+func (r *psqlBundleRepository) Fetch(ctx context.Context) ([]domain.Bundle, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, publisher_id, bundle_name, price, created_at FROM bundles ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Bundle
+	for rows.Next() {
+		var b domain.Bundle
+		if err := rows.Scan(&b.ID, &b.PublisherID, &b.Name, &b.Price, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range res {
+		if res[i].Games, err = r.getBundleGames(ctx, res[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *psqlBundleRepository) GetByID(ctx context.Context, id int) (domain.Bundle, error) {
+	var b domain.Bundle
+	err := r.db.QueryRowContext(ctx, `SELECT id, publisher_id, bundle_name, price, created_at FROM bundles WHERE id = $1`, id).
+		Scan(&b.ID, &b.PublisherID, &b.Name, &b.Price, &b.CreatedAt)
+	if err == sql.ErrNoRows {
+		return domain.Bundle{}, domain.ErrBundleNotFound
+	}
+	if err != nil {
+		return domain.Bundle{}, err
+	}
+
+	b.Games, err = r.getBundleGames(ctx, id)
+	return b, err
+}
+
+// getBundleGames includes soft-deleted games so a bundle never silently
+// shrinks; buyers are told the bundle is unavailable instead.
+func (r *psqlBundleRepository) getBundleGames(ctx context.Context, bundleID int) ([]domain.BundleGame, error) {
+	query := `
+		SELECT g.id, g.game_name, g.price, g.stock_level, g.parent_game_id, g.deleted_at
+		FROM bundle_games bg
+		JOIN games g ON g.id = bg.game_id
+		WHERE bg.bundle_id = $1
+		ORDER BY g.id`
+
+	rows, err := r.db.QueryContext(ctx, query, bundleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []domain.BundleGame
+	for rows.Next() {
+		var g domain.BundleGame
+		if err := rows.Scan(&g.ID, &g.Name, &g.Price, &g.StockLevel, &g.ParentGameID, &g.DeletedAt); err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
+func (r *psqlBundleRepository) Store(ctx context.Context, b *domain.Bundle, gameIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO bundles (publisher_id, bundle_name, price) VALUES ($1, $2, $3)
+		RETURNING id, created_at`, b.PublisherID, b.Name, b.Price).Scan(&b.ID, &b.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := setBundleGames(ctx, tx, b.ID, gameIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *psqlBundleRepository) Update(ctx context.Context, b *domain.Bundle, gameIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE bundles SET bundle_name = $1, price = $2, updated_at = NOW() WHERE id = $3`,
+		b.Name, b.Price, b.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrBundleNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bundle_games WHERE bundle_id = $1`, b.ID); err != nil {
+		return err
+	}
+	if err := setBundleGames(ctx, tx, b.ID, gameIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func setBundleGames(ctx context.Context, tx *sql.Tx, bundleID int, gameIDs []int) error {
+	ids := make([]int64, len(gameIDs))
+	for i, id := range gameIDs {
+		ids[i] = int64(id)
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO bundle_games (bundle_id, game_id)
+		SELECT $1, unnest($2::int[])`, bundleID, pq.Array(ids))
+	return err
+}
+
+func (r *psqlBundleRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM bundles WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrBundleNotFound
+	}
+	return nil
+}
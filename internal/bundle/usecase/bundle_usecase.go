@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("cool-games/internal/bundle/usecase")
+
+type bundleUsecase struct {
+	bundleRepo     domain.BundleRepository
+	gameRepo       domain.GameRepository
+	libraryRepo    domain.LibraryRepository
+	prices         domain.PriceResolver
+	contextTimeout time.Duration
+}
+
+func NewBundleUsecase(b domain.BundleRepository, g domain.GameRepository, l domain.LibraryRepository, p domain.PriceResolver, timeout time.Duration) domain.BundleUsecase {
+	return &bundleUsecase{
+		bundleRepo:     b,
+		gameRepo:       g,
+		libraryRepo:    l,
+		prices:         p,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *bundleUsecase) GetAll(ctx context.Context) ([]domain.Bundle, error) {
+	ctx, span := tracer.Start(ctx, "BundleUsecase.GetAll")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.bundleRepo.Fetch(c)
+}
+
+func (u *bundleUsecase) GetByID(ctx context.Context, id int) (domain.Bundle, error) {
+	ctx, span := tracer.Start(ctx, "BundleUsecase.GetByID")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.bundleRepo.GetByID(c, id)
+}
+
+func (u *bundleUsecase) Create(ctx context.Context, req domain.BundleRequest, requesterID int) (domain.Bundle, error) {
+	ctx, span := tracer.Start(ctx, "BundleUsecase.Create")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	pubID, err := u.gameRepo.GetPublisherIDByUserID(c, requesterID)
+	if err != nil {
+		return domain.Bundle{}, err
+	}
+	if err := u.validateGames(c, req.GameIDs, pubID); err != nil {
+		return domain.Bundle{}, err
+	}
+
+	b := domain.Bundle{PublisherID: pubID, Name: req.Name, Price: req.Price}
+	if err := u.bundleRepo.Store(c, &b, req.GameIDs); err != nil {
+		return domain.Bundle{}, err
+	}
+	return u.bundleRepo.GetByID(c, b.ID)
+}
+
+func (u *bundleUsecase) Update(ctx context.Context, id int, req domain.BundleRequest, requesterID int, role string) (domain.Bundle, error) {
+	ctx, span := tracer.Start(ctx, "BundleUsecase.Update")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.bundleRepo.GetByID(c, id)
+	if err != nil {
+		return domain.Bundle{}, err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return domain.Bundle{}, err
+	}
+	if err := u.validateGames(c, req.GameIDs, existing.PublisherID); err != nil {
+		return domain.Bundle{}, err
+	}
+
+	existing.Name = req.Name
+	existing.Price = req.Price
+	if err := u.bundleRepo.Update(c, &existing, req.GameIDs); err != nil {
+		return domain.Bundle{}, err
+	}
+	return u.bundleRepo.GetByID(c, id)
+}
+
+func (u *bundleUsecase) Delete(ctx context.Context, id int, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "BundleUsecase.Delete")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.bundleRepo.GetByID(c, id)
+	if err != nil {
+		return err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return err
+	}
+	return u.bundleRepo.Delete(c, id)
+}
+
+// Quote prices the bundle for a customer, leaving out the games they own, in
+// the customer's wallet currency.
+func (u *bundleUsecase) Quote(ctx context.Context, id int, customerUserID int) (domain.BundleQuote, error) {
+	ctx, span := tracer.Start(ctx, "BundleUsecase.Quote")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	b, err := u.bundleRepo.GetByID(c, id)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+	for _, g := range b.Games {
+		if g.DeletedAt != nil {
+			return domain.BundleQuote{}, domain.ErrBundleUnavailable
+		}
+	}
+
+	ownedGames, err := u.libraryRepo.GetOwnedGames(c, customerUserID)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+	owned := map[int]bool{}
+	for _, g := range ownedGames {
+		owned[g.ID] = true
+	}
+
+	q := b.Quote(owned)
+	if q.Price == 0 {
+		return domain.BundleQuote{}, domain.ErrBundleFullyOwned
+	}
+	return u.prices.ConvertQuote(c, q, customerUserID)
+}
+
+// validateGames requires distinct, live games that all belong to publisherID.
+func (u *bundleUsecase) validateGames(ctx context.Context, gameIDs []int, publisherID int) error {
+	verr := domain.NewValidationError()
+	seen := map[int]bool{}
+	for _, id := range gameIDs {
+		if seen[id] {
+			verr.Add("game_ids", "games must not repeat")
+			break
+		}
+		seen[id] = true
+
+		g, err := u.gameRepo.GetByID(ctx, id)
+		if err == domain.ErrGameNotFound {
+			verr.Add("game_ids", fmt.Sprintf("game %d not found", id))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if g.PublisherID != publisherID {
+			verr.Add("game_ids", fmt.Sprintf("game %d belongs to another publisher", id))
+		}
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+func (u *bundleUsecase) authorizeOwner(ctx context.Context, b domain.Bundle, requesterID int, role string) error {
+	if role == "admin" {
+		return nil
+	}
+	pubID, err := u.gameRepo.GetPublisherIDByUserID(ctx, requesterID)
+	if err != nil || b.PublisherID != pubID {
+		return domain.ErrUnauthorizedAction
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+const (
+	GameAuditUpdate = "update"
+	GameAuditPatch  = "patch"
+)
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// GameAuditEntry records who changed which fields of a game, keyed by the
+// JSON field name clients use.
+type GameAuditEntry struct {
+	ID            int                    `json:"id"`
+	GameID        int                    `json:"game_id"`
+	ActorUserID   int                    `json:"actor_user_id"`
+	Action        string                 `json:"action"`
+	ChangedFields []string               `json:"changed_fields"`
+	Changes       map[string]FieldChange `json:"changes"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
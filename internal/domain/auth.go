@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+var (
+	ErrUnauthenticated    = NewError(KindUnauthenticated, "unauthenticated", "authentication required")
+	ErrInvalidToken       = NewError(KindUnauthenticated, "invalid_token", "invalid or expired token")
+	ErrInvalidCredentials = NewError(KindUnauthenticated, "invalid_credentials", "invalid email or password")
+	ErrUserNotFound       = NewError(KindNotFound, "user_not_found", "user not found")
+	ErrEmailTaken         = NewError(KindConflict, "email_taken", "an account with this email already exists")
+	ErrRoleForbidden      = NewError(KindForbidden, "role_forbidden", "your role is not authorized for this action")
+)
+
 type User struct {
 	ID             int        `json:"id"`
 	Email          string     `json:"email" binding:"required,email"`
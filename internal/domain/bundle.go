@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+var (
+	ErrBundleNotFound     = NewError(KindNotFound, "bundle_not_found", "bundle not found")
+	ErrBundleFullyOwned   = NewError(KindConflict, "bundle_fully_owned", "you already own every game in this bundle")
+	ErrBundleUnavailable  = NewError(KindConflict, "bundle_unavailable", "a game in this bundle is no longer available")
+	ErrBundleLibraryStale = NewError(KindConflict, "bundle_library_stale", "your library changed while buying, please check the price again")
+)
+
+// Bundle sells several games of one publisher for Price instead of the sum of
+// their list prices.
+type Bundle struct {
+	ID          int          `json:"id"`
+	PublisherID int          `json:"publisher_id"`
+	Name        string       `json:"bundle_name"`
+	Price       float64      `json:"price"`
+	Games       []BundleGame `json:"games"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// BundleGame is a game as contained in a bundle, with its current list price.
+type BundleGame struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"game_name"`
+	Price        float64    `json:"price"`
+	StockLevel   int        `json:"stock_level"`
+	ParentGameID *int       `json:"parent_game_id"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
+
+type BundleRequest struct {
+	Name    string  `json:"bundle_name" binding:"required"`
+	Price   float64 `json:"price" binding:"required,gt=0"`
+	GameIDs []int   `json:"game_ids" binding:"required,min=2,dive,gt=0"`
+}
+
+type BundlePurchaseRequest struct {
+	BundleID int `json:"bundle_id" binding:"required"`
+}
+
+// BundleQuoteItem is one game of a bundle with its share of the bundle price,
+// in the quote currency and in the base currency.
+type BundleQuoteItem struct {
+	GameID     int     `json:"game_id"`
+	GameName   string  `json:"game_name"`
+	Price      float64 `json:"price"`
+	BaseAmount float64 `json:"base_amount"`
+	Owned      bool    `json:"owned"`
+}
+
+// BundleQuote is what a customer pays for a bundle given what they already
+// own. Price is the sum of the items not owned yet; BasePrice is the same in
+// the base currency.
+type BundleQuote struct {
+	BundleID    int               `json:"bundle_id"`
+	Currency    string            `json:"currency"`
+	BundlePrice float64           `json:"bundle_price"`
+	Price       float64           `json:"price"`
+	BasePrice   float64           `json:"base_price"`
+	Items       []BundleQuoteItem `json:"items"`
+}
+
+// Quote prices the bundle, in the base currency, for a customer who owns the
+// given games ("complete the bundle"). The bundle price is split across the
+// games in proportion to their list prices, and owned games drop their share.
+func (b Bundle) Quote(owned map[int]bool) BundleQuote {
+	q := BundleQuote{BundleID: b.ID, BundlePrice: b.Price}
+
+	var listTotal float64
+	for _, g := range b.Games {
+		listTotal += g.Price
+	}
+
+	var allocated float64
+	for i, g := range b.Games {
+		share := b.Price / float64(len(b.Games))
+		if listTotal > 0 {
+			share = b.Price * g.Price / listTotal
+		}
+		share = RoundCents(share)
+		// The last share absorbs rounding so the shares add up to the bundle price.
+		if i == len(b.Games)-1 {
+			share = RoundCents(b.Price - allocated)
+		}
+		allocated += share
+
+		item := BundleQuoteItem{GameID: g.ID, GameName: g.Name, Price: share, BaseAmount: share, Owned: owned[g.ID]}
+		if !item.Owned {
+			q.Price += share
+		}
+		q.Items = append(q.Items, item)
+	}
+	q.Price = RoundCents(q.Price)
+	q.BasePrice = q.Price
+	return q
+}
+
+// RoundCents rounds an amount of money to two decimals.
+func RoundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// BundlePurchaseLine is one game bought as part of a bundle, at its share of
+// the bundle price in the customer's currency and in the base currency.
+type BundlePurchaseLine struct {
+	GameID     int
+	Price      float64
+	BaseAmount float64
+}
+
+type BundleRepository interface {
+	Fetch(ctx context.Context) ([]Bundle, error)
+	GetByID(ctx context.Context, id int) (Bundle, error)
+	Store(ctx context.Context, b *Bundle, gameIDs []int) error
+	Update(ctx context.Context, b *Bundle, gameIDs []int) error
+	Delete(ctx context.Context, id int) error
+}
+
+type BundleUsecase interface {
+	GetAll(ctx context.Context) ([]Bundle, error)
+	GetByID(ctx context.Context, id int) (Bundle, error)
+	Create(ctx context.Context, req BundleRequest, requesterID int) (Bundle, error)
+	Update(ctx context.Context, id int, req BundleRequest, requesterID int, role string) (Bundle, error)
+	Delete(ctx context.Context, id int, requesterID int, role string) error
+	Quote(ctx context.Context, id int, customerUserID int) (BundleQuote, error)
+}
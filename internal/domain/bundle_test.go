@@ -0,0 +1,80 @@
+package domain
+
+import "testing"
+
+func TestBundleQuote(t *testing.T) {
+	tests := []struct {
+		name      string
+		price     float64
+		listPrice []float64
+		owned     map[int]bool
+		shares    []float64
+		want      float64
+	}{
+		{
+			name:      "shares follow list prices",
+			price:     30,
+			listPrice: []float64{20, 40},
+			shares:    []float64{10, 20},
+			want:      30,
+		},
+		{
+			name:      "last share absorbs rounding",
+			price:     10,
+			listPrice: []float64{5, 5, 5},
+			shares:    []float64{3.33, 3.33, 3.34},
+			want:      10,
+		},
+		{
+			name:      "owned games drop their share",
+			price:     30,
+			listPrice: []float64{20, 40},
+			owned:     map[int]bool{2: true},
+			shares:    []float64{10, 20},
+			want:      10,
+		},
+		{
+			name:      "everything owned costs nothing",
+			price:     30,
+			listPrice: []float64{20, 40},
+			owned:     map[int]bool{1: true, 2: true},
+			shares:    []float64{10, 20},
+			want:      0,
+		},
+		{
+			name:      "free games split evenly",
+			price:     9,
+			listPrice: []float64{0, 0, 0},
+			shares:    []float64{3, 3, 3},
+			want:      9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bundle{ID: 7, Price: tt.price}
+			for i, p := range tt.listPrice {
+				b.Games = append(b.Games, BundleGame{ID: i + 1, Price: p})
+			}
+
+			q := b.Quote(tt.owned)
+
+			if q.BundleID != 7 || q.BundlePrice != tt.price {
+				t.Errorf("quote is for bundle %d at %v, want 7 at %v", q.BundleID, q.BundlePrice, tt.price)
+			}
+			if q.Price != tt.want || q.BasePrice != tt.want {
+				t.Errorf("Price = %v, BasePrice = %v, want %v", q.Price, q.BasePrice, tt.want)
+			}
+			if len(q.Items) != len(tt.shares) {
+				t.Fatalf("got %d items, want %d", len(q.Items), len(tt.shares))
+			}
+			for i, item := range q.Items {
+				if item.Price != tt.shares[i] || item.BaseAmount != tt.shares[i] {
+					t.Errorf("item %d share = %v (base %v), want %v", i, item.Price, item.BaseAmount, tt.shares[i])
+				}
+				if item.Owned != tt.owned[item.GameID] {
+					t.Errorf("item %d owned = %v", i, item.Owned)
+				}
+			}
+		})
+	}
+}
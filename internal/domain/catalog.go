@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+var (
+	ErrInvalidCatalogFile = NewError(KindMalformed, "invalid_catalog_file", "the catalog file could not be read")
+	ErrCatalogTooLarge    = NewError(KindTooLarge, "catalog_too_large", "the catalog file is larger than 10 MB")
+
+	// ErrDeveloperNotFound and ErrGameOutOfRange report a row the database
+	// refused, e.g. because its developer was deleted after validation.
+	ErrDeveloperNotFound = NewError(KindInvalid, "developer_not_found", "developer does not exist")
+	ErrGameOutOfRange    = NewError(KindInvalid, "game_out_of_range", "a value is too large to store")
+)
+
+const (
+	CatalogFormatCSV   = "csv"
+	CatalogFormatJSONL = "jsonl"
+
+	ImportModeTransactional = "transactional"
+	ImportModeBestEffort    = "best_effort"
+
+	ImportRowValid   = "valid"
+	ImportRowCreated = "created"
+	ImportRowFailed  = "failed"
+	ImportRowSkipped = "skipped"
+)
+
+// CatalogRow is one game in an import or export file. Genres are referenced
+// by name and ReleaseDate uses YYYY-MM-DD. The developer is given by name, by
+// DeveloperID, or both; an import needs the id when several developers share
+// the name.
+type CatalogRow struct {
+	Name        string   `json:"name"`
+	Developer   string   `json:"developer,omitempty"`
+	DeveloperID int      `json:"developer_id,omitempty"`
+	Price       float64  `json:"price"`
+	Stock       int      `json:"stock"`
+	Genres      []string `json:"genres"`
+	ReleaseDate string   `json:"release_date,omitempty"`
+}
+
+type ImportOptions struct {
+	Format string
+	Mode   string
+	DryRun bool
+}
+
+type ImportRowResult struct {
+	Row    int      `json:"row"`
+	Name   string   `json:"name"`
+	Status string   `json:"status"`
+	GameID int      `json:"game_id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	Mode      string            `json:"mode"`
+	DryRun    bool              `json:"dry_run"`
+	Committed bool              `json:"committed"`
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+type CatalogRepository interface {
+	Store(ctx context.Context, game *Game) error
+	StoreMany(ctx context.Context, games []*Game) error
+	FetchByPublisher(ctx context.Context, publisherID int) ([]Game, error)
+	Fetch(ctx context.Context, filter GameFilter) ([]Game, error)
+	GetPublisherIDByUserID(ctx context.Context, userID int) (int, error)
+	GetGenresByName(ctx context.Context, names []string) (map[string]Genre, error)
+	// GetDeveloperNames maps the ids that exist to their developer's name.
+	GetDeveloperNames(ctx context.Context, ids []int) (map[int]string, error)
+	// GetDeveloperIDsByName maps lower-cased names to the developers that
+	// have them.
+	GetDeveloperIDsByName(ctx context.Context, names []string) (map[string][]int, error)
+}
+
+type CatalogUsecase interface {
+	Import(ctx context.Context, r io.Reader, opts ImportOptions, requesterID int) (ImportReport, error)
+	Export(ctx context.Context, w io.Writer, format string, requesterID int, role string) error
+}
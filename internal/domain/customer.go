@@ -5,11 +5,17 @@ import (
 	"time"
 )
 
+var ErrCustomerNotFound = NewError(KindNotFound, "customer_not_found", "customer profile not found")
+
+// Customer balances are held in Currency. An empty Currency means the
+// customer never picked one and uses the base currency.
 type Customer struct {
 	ID             int       `json:"id"`
 	UserID         int       `json:"user_id"`
 	CustomerName   string    `json:"customer_name"`
 	CurrentBalance float64   `json:"current_balance"`
+	Region         string    `json:"region"`
+	Currency       string    `json:"currency"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
@@ -17,8 +23,10 @@ type CustomerRepository interface {
 	Create(ctx context.Context, customer *Customer) error
 	GetByUserID(ctx context.Context, userID int) (Customer, error)
 	UpdateBalance(ctx context.Context, userID int, amount float64) error
+	UpdateRegion(ctx context.Context, userID int, region, currency string, currencyChanged bool) error
 }
 
 type CustomerUsecase interface {
 	GetProfile(ctx context.Context, userID int) (Customer, error)
+	UpdateRegion(ctx context.Context, userID int, req RegionRequest) (Customer, error)
 }
\ No newline at end of file
@@ -0,0 +1,13 @@
+package domain
+
+var ErrBaseGameNotOwned = NewError(KindForbidden, "base_game_not_owned", "you need to own the base game to buy this add-on")
+
+// DLC is an add-on as listed under its base game. Owned is only ever true for
+// the customer making the request.
+type DLC struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"game_name"`
+	Price      float64 `json:"price"`
+	StockLevel int     `json:"stock_level"`
+	Owned      bool    `json:"owned"`
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies an Error by what went wrong, independent of transport.
+// The HTTP layer maps each kind to one status code.
+type ErrorKind string
+
+const (
+	// KindMalformed is a request that cannot be read at all: a body that does
+	// not decode or bind, or an unparseable parameter. KindInvalid is one
+	// that reads fine but breaks a rule.
+	KindMalformed       ErrorKind = "malformed"
+	KindInvalid         ErrorKind = "invalid"
+	KindUnauthenticated ErrorKind = "unauthenticated"
+	KindForbidden       ErrorKind = "forbidden"
+	KindNotFound        ErrorKind = "not_found"
+	KindConflict        ErrorKind = "conflict"
+	KindPrecondition    ErrorKind = "precondition_failed"
+	KindTooLarge        ErrorKind = "too_large"
+	KindUnsupported     ErrorKind = "unsupported_media_type"
+	KindUnavailable     ErrorKind = "unavailable"
+	KindInternal        ErrorKind = "internal"
+)
+
+// Error is the error type usecases and repositories return. Code is a stable,
+// machine-readable identifier such as "game_not_found"; Message is for people.
+// Package-level sentinels are compared with errors.Is, which matches on Code,
+// so a sentinel enriched with WithDetails still matches.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Details map[string]interface{}
+	Err     error
+}
+
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails returns a copy of e carrying extra context, e.g. the game that
+// ran out of stock.
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	cp := *e
+	cp.Details = details
+	return &cp
+}
+
+// Wrap returns a copy of e that keeps err as its cause.
+func (e *Error) Wrap(err error) *Error {
+	cp := *e
+	cp.Err = err
+	return &cp
+}
+
+var (
+	// ErrInvalidBody wraps a request body that does not decode or bind.
+	ErrInvalidBody  = NewError(KindMalformed, "invalid_body", "invalid request body")
+	ErrInvalidQuery = NewError(KindMalformed, "invalid_query", "invalid query parameter")
+
+	// ErrDraining and ErrDatabaseDown fail the readiness probe.
+	ErrDraining     = NewError(KindUnavailable, "draining", "server is shutting down")
+	ErrDatabaseDown = NewError(KindUnavailable, "database_unavailable", "database is unreachable")
+)
+
+// Invalid reports a malformed request that has no sentinel of its own.
+func Invalid(format string, args ...interface{}) *Error {
+	return NewError(KindMalformed, "invalid_request", fmt.Sprintf(format, args...))
+}
+
+// Unsupported reports a request body in a media type the endpoint does not take.
+func Unsupported(message string) *Error {
+	return NewError(KindUnsupported, "unsupported_media_type", message)
+}
+
+// KindOf reports how err should be treated. Anything that is not an Error or
+// ValidationError is an internal failure.
+func KindOf(err error) ErrorKind {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return KindInvalid
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
@@ -2,47 +2,108 @@ package domain
 
 import (
 	"context"
-	"errors"
 	"time"
 )
 
 var (
-	ErrUnauthorizedAction = errors.New("you are not authorized to modify this resource")
-	ErrGameNotFound       = errors.New("game not found")
+	ErrUnauthorizedAction = NewError(KindForbidden, "forbidden", "you are not authorized to modify this resource")
+	ErrGameNotFound       = NewError(KindNotFound, "game_not_found", "game not found")
+	ErrVersionConflict    = NewError(KindPrecondition, "version_conflict", "game was modified by someone else, reload it and try again")
+	ErrInvalidMergePatch  = NewError(KindMalformed, "invalid_merge_patch", "merge patch must be a JSON object")
+	ErrPublisherNotFound  = NewError(KindForbidden, "publisher_profile_missing", "publisher profile not found")
+
+	// ErrGamePublisherNotFound is a game whose publisher no longer exists.
+	ErrGamePublisherNotFound = NewError(KindNotFound, "publisher_not_found", "publisher not found")
 )
 
 type Game struct {
     ID          int       `json:"id"`
     PublisherID int       `json:"publisher_id"`
+    ParentGameID *int     `json:"parent_game_id"`
     DeveloperID int       `json:"developer_id" binding:"required"`
     Name        string    `json:"game_name" binding:"required"`
     Price       float64   `json:"price" binding:"required"`
+    LowestPrice30d float64 `json:"lowest_price_30d"`
+    LocalPrice  *Price    `json:"local_price,omitempty"`
     StockLevel  int       `json:"stock_level"`
+    ShortDescription        string              `json:"short_description"`
+    Description             string              `json:"description"`
+    MinRequirements         *SystemRequirements `json:"min_requirements"`
+    RecommendedRequirements *SystemRequirements `json:"recommended_requirements"`
+    AgeRatingSystem         string              `json:"age_rating_system"`
+    AgeRating               string              `json:"age_rating"`
+    MinimumAge              *int                `json:"minimum_age"`
+    Languages               []string            `json:"languages"`
     Genres      []Genre   `json:"genres"`
+    Media       []GameMedia `json:"media"`
+    DLC         []DLC     `json:"dlc,omitempty"`
     ReleaseDate time.Time `json:"release_date"`
+    Version     int       `json:"version"`
+    DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
+// RestockRequest adds Amount copies. A correction may also be negative, to
+// fix a miscount; a manual restock (the default) only adds stock.
 type RestockRequest struct {
-	Amount int `json:"amount" binding:"required,gt=0"`
+	Amount int    `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"omitempty,oneof=manual_restock correction"`
+}
+
+// StockRule is a publisher's low-stock configuration for one game. A zero
+// threshold disables alerts, a zero AutoRestockAmount disables auto-restock.
+type StockRule struct {
+	GameID            int `json:"game_id"`
+	LowStockThreshold int `json:"low_stock_threshold" binding:"gte=0"`
+	AutoRestockAmount int `json:"auto_restock_amount" binding:"gte=0"`
+}
+
+// StockMonitor reacts to stock changes made outside GameUsecase, such as
+// purchases, by raising low-stock alerts and applying auto-restock rules.
+// StockChanged returns at once; the change is handled after the caller's
+// request, whose cancellation does not stop it.
+type StockMonitor interface {
+	StockChanged(ctx context.Context, gameID int, previous, current int)
 }
 
 type GameRepository interface {
-	Fetch(ctx context.Context, search string, minPrice, maxPrice float64) ([]Game, error)
+	Fetch(ctx context.Context, filter GameFilter) ([]Game, error)
 	GetByID(ctx context.Context, id int) (Game, error)
+	FetchDLC(ctx context.Context, baseGameID int, userID int) ([]DLC, error)
 	Store(ctx context.Context, game *Game) error
-	Update(ctx context.Context, game *Game) error
+	Update(ctx context.Context, game *Game, audit *GameAuditEntry) error
 	Delete(ctx context.Context, id int) error
-	UpdateStock(ctx context.Context, gameID int, change int) error
+	UpdateStock(ctx context.Context, change StockChange) error
 	FetchByPublisher(ctx context.Context, publisherID int) ([]Game, error)
 	GetPublisherIDByUserID(ctx context.Context, userID int) (int, error)
+	GetPublisherUserID(ctx context.Context, publisherID int) (int, error)
+	GetStockRule(ctx context.Context, gameID int) (StockRule, error)
+	SaveStockRule(ctx context.Context, rule *StockRule) error
+	FetchDeleted(ctx context.Context, publisherID int) ([]Game, error)
+	GetDeletedByID(ctx context.Context, id int) (Game, error)
+	Restore(ctx context.Context, id int) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, []string, error)
+	FetchStockHistory(ctx context.Context, gameID int, filter StockHistoryFilter) ([]StockHistoryEntry, int, error)
+	FetchDailyStockSummary(ctx context.Context, gameID int, filter StockHistoryFilter) ([]DailyStockSummary, error)
+	FetchAuditTrail(ctx context.Context, gameID int) ([]GameAuditEntry, error)
+	FetchPriceHistory(ctx context.Context, gameID int) ([]PriceHistoryEntry, error)
 }
 
 type GameUsecase interface {
-    GetAll(ctx context.Context, search string, minPrice, maxPrice float64) ([]Game, error) 
-    GetByID(ctx context.Context, id int) (Game, error)
+    GetAll(ctx context.Context, filter GameFilter) ([]Game, error)
+    GetByID(ctx context.Context, id int, viewerUserID int) (Game, error)
     GetByPublisher(ctx context.Context, publisherID int) ([]Game, error)
     Create(ctx context.Context, game *Game, requesterID int) error
-    Update(ctx context.Context, id int, game *Game, requesterID int, role string) error
+    Update(ctx context.Context, id int, game *Game, requesterID int, role string, expectedVersion int) error
+    Patch(ctx context.Context, id int, patch []byte, requesterID int, role string, expectedVersion int) (Game, error)
+    GetAuditTrail(ctx context.Context, id int, requesterID int, role string) ([]GameAuditEntry, error)
+    GetPriceHistory(ctx context.Context, id int, requesterID int, role string) ([]PriceHistoryEntry, error)
     Delete(ctx context.Context, id int, requesterID int, role string) error 
-    Restock(ctx context.Context, gameID int, requesterID int, amount int) error
+    Restock(ctx context.Context, gameID int, requesterID int, req RestockRequest) error
+    GetStockRule(ctx context.Context, gameID int, requesterID int, role string) (StockRule, error)
+    SetStockRule(ctx context.Context, rule *StockRule, requesterID int, role string) error
+    GetStockHistory(ctx context.Context, gameID int, requesterID int, role string, filter StockHistoryFilter) (StockHistoryPage, error)
+    GetDailyStockSummary(ctx context.Context, gameID int, requesterID int, role string, filter StockHistoryFilter) ([]DailyStockSummary, error)
+    GetTrash(ctx context.Context, requesterID int, role string) ([]Game, error)
+    Restore(ctx context.Context, id int, requesterID int, role string) (Game, error)
+    PurgeTrash(ctx context.Context, retention time.Duration) (int, error)
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package domain
+
+const (
+	AgeRatingPEGI = "PEGI"
+	AgeRatingESRB = "ESRB"
+)
+
+// ageRatings maps every accepted rating to the minimum age it implies. ESRB
+// "RP" (rating pending) has no age yet and is left out of max_age filters.
+var ageRatings = map[string]map[string]int{
+	AgeRatingPEGI: {"3": 3, "7": 7, "12": 12, "16": 16, "18": 18},
+	AgeRatingESRB: {"E": 0, "E10+": 10, "T": 13, "M": 17, "AO": 18, "RP": -1},
+}
+
+// SystemRequirements is stored as JSON; every field is free text such as
+// "8 GB RAM" so publishers can describe hardware the way they are used to.
+type SystemRequirements struct {
+	OS        string `json:"os,omitempty"`
+	Processor string `json:"processor,omitempty"`
+	Memory    string `json:"memory,omitempty"`
+	Graphics  string `json:"graphics,omitempty"`
+	Storage   string `json:"storage,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+}
+
+// ValidAgeRating reports whether rating exists in the given rating system.
+func ValidAgeRating(system, rating string) bool {
+	_, ok := ageRatings[system][rating]
+	return ok
+}
+
+// MinimumAge returns the age a rating implies, or nil for unrated games and
+// pending ratings.
+func MinimumAge(system, rating string) *int {
+	age, ok := ageRatings[system][rating]
+	if !ok || age < 0 {
+		return nil
+	}
+	return &age
+}
+
+// GameFilter narrows GET /games. Zero values mean "no filter".
+type GameFilter struct {
+	Search          string
+	MinPrice        float64
+	MaxPrice        float64
+	AgeRatingSystem string
+	AgeRating       string
+	MaxAge          int
+	Language        string
+	Genre           string // genre ID or slug
+	Tag             string
+}
@@ -1,22 +1,74 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"regexp"
+	"strings"
+)
 
+var (
+	ErrGenreNotFound    = NewError(KindNotFound, "genre_not_found", "genre not found")
+	ErrParentNotFound   = NewError(KindInvalid, "parent_genre_not_found", "parent genre not found")
+	ErrGenreNameMissing = NewError(KindInvalid, "genre_name_missing", "genre_name is required")
+	ErrGenreExists      = NewError(KindConflict, "genre_exists", "a genre with this name or slug already exists")
+	ErrGenreInUse       = NewError(KindConflict, "genre_in_use", "genre is still assigned to games or has subgenres; pass force=true to delete it anyway")
+	ErrGenreCycle       = NewError(KindInvalid, "genre_cycle", "a genre cannot be moved under itself or one of its subgenres")
+	ErrGenreMergeTarget = NewError(KindInvalid, "genre_merge_target", "a genre cannot be merged into itself or one of its subgenres")
+	ErrInvalidSlug      = NewError(KindInvalid, "invalid_slug", "slugs are lowercase letters and digits separated by single dashes")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Genre may sit under a parent genre, e.g. RTS under Strategy. Children is
+// only filled in the genre tree. The slug is set once, from the name unless
+// given, so URLs keep working when a genre is renamed.
 type Genre struct {
-	ID   int    `json:"id"`
-	Name string `json:"genre_name"`
+	ID       int     `json:"id"`
+	Name     string  `json:"genre_name"`
+	Slug     string  `json:"slug"`
+	ParentID *int    `json:"parent_id"`
+	Children []Genre `json:"children,omitempty"`
+}
+
+// Slugify turns a genre name such as "Role-Playing & Strategy" into
+// "role-playing-strategy".
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+func ValidSlug(slug string) bool {
+	return slugPattern.MatchString(slug)
 }
 
 type GenreRepository interface {
-    Fetch(ctx context.Context) ([]Genre, error)
-    Store(ctx context.Context, genre *Genre) error
-    Update(ctx context.Context, genre *Genre) error
-    Delete(ctx context.Context, id int) error
+	Fetch(ctx context.Context) ([]Genre, error)
+	GetByID(ctx context.Context, id int) (Genre, error)
+	GetBySlug(ctx context.Context, slug string) (Genre, error)
+	Store(ctx context.Context, genre *Genre) error
+	Update(ctx context.Context, genre *Genre) error
+	Delete(ctx context.Context, id int, force bool) error
+	MergeInto(ctx context.Context, sourceID, targetID int) error
 }
 
 type GenreUsecase interface {
-    GetAll(ctx context.Context) ([]Genre, error)
-    Create(ctx context.Context, genre *Genre) error
-    Update(ctx context.Context, genre *Genre) error
-    Delete(ctx context.Context, id int) error
-}
\ No newline at end of file
+	GetAll(ctx context.Context) ([]Genre, error)
+	GetTree(ctx context.Context) ([]Genre, error)
+	Get(ctx context.Context, idOrSlug string) (Genre, error)
+	Create(ctx context.Context, genre *Genre) error
+	Update(ctx context.Context, genre *Genre) error
+	Delete(ctx context.Context, id int, force bool) error
+	MergeInto(ctx context.Context, sourceID, targetID int) (Genre, error)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Action", "action"},
+		{"Role-Playing & Strategy", "role-playing-strategy"},
+		{"  Sci-Fi  ", "sci-fi"},
+		{"Shoot 'em up", "shoot-em-up"},
+		{"4X", "4x"},
+		{"--Indie--", "indie"},
+		{"Café Racer", "caf-racer"},
+		{"日本語", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.name)
+			if got != tt.want {
+				t.Fatalf("Slugify(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+			if got != "" && !ValidSlug(got) {
+				t.Errorf("Slugify(%q) = %q, which ValidSlug rejects", tt.name, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+var (
+	ErrRestockNotPositive = NewError(KindInvalid, "restock_not_positive", "a restock must add stock, use a correction to remove copies")
+	ErrAutoRestockNoLimit = NewError(KindInvalid, "auto_restock_without_threshold", "auto restock requires a low stock threshold")
+)
+
+// Reasons recorded with every row of game_quantity_history.
+const (
+	StockReasonPurchase      = "purchase"
+	StockReasonManualRestock = "manual_restock"
+	StockReasonAutoRestock   = "auto_restock"
+	StockReasonRefund        = "refund"
+	StockReasonCorrection    = "correction"
+)
+
+// StockChange is a single stock movement. ActorUserID is nil for changes the
+// system made on its own, such as automatic restocks.
+type StockChange struct {
+	GameID      int
+	Change      int
+	Reason      string
+	ActorUserID *int
+}
+
+type StockHistoryEntry struct {
+	ID              int       `json:"id"`
+	GameID          int       `json:"game_id"`
+	ChangeAmount    int       `json:"change_amount"`
+	Reason          string    `json:"reason"`
+	ActorUserID     *int      `json:"actor_user_id"`
+	TransactionDate time.Time `json:"transaction_date"`
+}
+
+// StockHistoryFilter bounds a history query. From is inclusive, To is
+// exclusive; nil leaves that side open.
+type StockHistoryFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	PageSize int
+}
+
+type StockHistoryPage struct {
+	Items    []StockHistoryEntry `json:"items"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+	Total    int                 `json:"total"`
+}
+
+type DailyStockSummary struct {
+	Date    string `json:"date"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Net     int    `json:"net"`
+	Changes int    `json:"changes"`
+}
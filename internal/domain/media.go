@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+var (
+	ErrMediaNotFound      = NewError(KindNotFound, "media_not_found", "media not found")
+	ErrMediaTooLarge      = NewError(KindTooLarge, "media_too_large", "file is too large")
+	ErrUnsupportedMedia   = NewError(KindUnsupported, "unsupported_media", "only JPEG, PNG and GIF images are accepted")
+	ErrInvalidMediaKind   = NewError(KindInvalid, "invalid_media_kind", "kind must be cover or screenshot")
+	ErrTooManyScreenshots = NewError(KindConflict, "too_many_screenshots", "screenshot limit reached for this game")
+)
+
+const (
+	MediaKindCover      = "cover"
+	MediaKindScreenshot = "screenshot"
+)
+
+type GameMedia struct {
+	ID           int       `json:"id"`
+	GameID       int       `json:"game_id"`
+	Kind         string    `json:"kind"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	StorageKey   string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BlobStore keeps uploaded files under opaque keys and knows the public URL
+// each key is served from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+type MediaRepository interface {
+	Store(ctx context.Context, m *GameMedia) error
+	GetByID(ctx context.Context, id int) (GameMedia, error)
+	FetchByGame(ctx context.Context, gameID int) ([]GameMedia, error)
+	CountByKind(ctx context.Context, gameID int, kind string) (int, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type MediaUsecase interface {
+	Upload(ctx context.Context, gameID int, kind string, file io.Reader, requesterID int, role string) (GameMedia, error)
+	GetByGame(ctx context.Context, gameID int) ([]GameMedia, error)
+	Delete(ctx context.Context, gameID int, mediaID int, requesterID int, role string) error
+}
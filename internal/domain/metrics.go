@@ -0,0 +1,18 @@
+package domain
+
+const (
+	PurchaseKindGame   = "game"
+	PurchaseKindBundle = "bundle"
+)
+
+// BusinessMetrics counts business events for monitoring. Usecases report to
+// it after the fact; it must not fail or block them.
+type BusinessMetrics interface {
+	// PurchaseCompleted records a sale of kind charged in currency.
+	PurchaseCompleted(kind string, amount float64, currency string)
+	// PurchaseFailed records a purchase of kind rejected with err; the
+	// error code becomes the reason.
+	PurchaseFailed(kind string, err error)
+	BalanceToppedUp(amount float64, currency string)
+	UserRegistered(role string)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var ErrNotificationNotFound = NewError(KindNotFound, "notification_not_found", "notification not found")
+
+// Notification types, one per event that sends notifications.
+const (
+	NotificationPriceDrop         = "price_drop"
+	NotificationRestock           = "restock"
+	NotificationPurchaseCompleted = "purchase_completed"
+	NotificationLowStock          = "low_stock"
+)
+
+type Notification struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type NotificationList struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int            `json:"unread_count"`
+}
+
+// Notifier delivers a notification to a single user. Implementations decide
+// the channel (log, file, database, ...), callers only describe the event.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+type NotificationRepository interface {
+	Store(ctx context.Context, n *Notification) error
+	FetchByUser(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, userID int, id int) error
+	MarkAllRead(ctx context.Context, userID int) error
+}
+
+type NotificationUsecase interface {
+	Notifier
+	GetNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) (NotificationList, error)
+	MarkRead(ctx context.Context, userID int, id int) error
+	MarkAllRead(ctx context.Context, userID int) error
+	Subscribe(userID int) (<-chan Notification, func())
+	CloseSubscribers()
+}
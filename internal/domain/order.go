@@ -5,6 +5,12 @@ import (
 	"time"
 )
 
+var (
+	ErrInsufficientBalance = NewError(KindConflict, "insufficient_balance", "insufficient balance")
+	ErrOutOfStock          = NewError(KindConflict, "out_of_stock", "game out of stock")
+	ErrAlreadyOwned        = NewError(KindConflict, "already_owned", "you already own this game")
+)
+
 type PurchaseRequest struct {
 	GameID int `json:"game_id" binding:"required"`
 }
@@ -13,19 +19,23 @@ type SalesReportEntry struct {
     GameID        int       `json:"game_id"`
     GameName      string    `json:"game_name"`
     PriceAtSale   float64   `json:"price_at_sale"`
+    Currency      string    `json:"currency"`
+    BaseAmount    float64   `json:"base_amount"`
     PurchasedDate time.Time `json:"purchased_date"`
     CustomerEmail string    `json:"customer_email"`
 }
 
 type OrderUsecase interface {
     BuyGame(ctx context.Context, customerID int, gameID int) error
+    BuyBundle(ctx context.Context, customerID int, bundleID int) (BundleQuote, error)
     GetPublisherSalesReport(ctx context.Context, customerID int) ([]SalesReportEntry, error)
     AddBalance(ctx context.Context, customerID int, amount float64) error
 	GetCustomerLibrary(ctx context.Context, userID int) ([]Game, error)
 }
 
 type OrderRepository interface {
-    ExecutePurchase(ctx context.Context, customerID int, gameID int, price float64) error
+    ExecutePurchase(ctx context.Context, customerID int, gameID int, price Price) (int, error)
+    ExecuteBundlePurchase(ctx context.Context, customerID int, bundleID int, currency string, lines []BundlePurchaseLine) (map[int]int, error)
 	GetPublisherSales(ctx context.Context, publisherID int) ([]SalesReportEntry, error)
-	RecordLedger(ctx context.Context, customerID int, amount float64, description string) error
+	RecordLedger(ctx context.Context, customerID int, amount float64, currency string, description string) error
 }
\ No newline at end of file
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// LowestPriceWindow is how far back Game.LowestPrice30d looks, as required
+// for announcing price reductions in the EU.
+const LowestPriceWindow = 30 * 24 * time.Hour
+
+// PriceHistoryEntry is a price a game was set to. The first entry is the
+// price it was created with.
+type PriceHistoryEntry struct {
+	ID          int       `json:"id"`
+	GameID      int       `json:"game_id"`
+	Price       float64   `json:"price"`
+	ActorUserID *int      `json:"actor_user_id"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	ErrExchangeRateNotFound  = NewError(KindNotFound, "exchange_rate_not_found", "no exchange rate for this currency")
+	ErrRegionalPriceNotFound = NewError(KindNotFound, "regional_price_not_found", "no price for this region")
+	ErrExchangeRateInUse     = NewError(KindConflict, "exchange_rate_in_use", "exchange rate is still used by wallets or regional prices")
+	ErrBaseCurrencyRate      = NewError(KindInvalid, "base_currency_rate", "the base currency always has a rate of 1")
+	ErrWalletNotEmpty        = NewError(KindConflict, "wallet_not_empty", "spend or withdraw your balance before changing currency")
+	ErrInvalidCurrency       = NewError(KindInvalid, "invalid_currency", "currency must be a 3 letter ISO 4217 code")
+	ErrInvalidRegion         = NewError(KindInvalid, "invalid_region", "region must be a 2 or 3 letter code such as US or EU")
+)
+
+// ExchangeRate is how many units of Currency one unit of the base currency
+// buys. Rates are maintained by admins; there is no live feed.
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate" binding:"required,gt=0"`
+	UpdatedBy *int      `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RegionalPrice overrides a game's base price for customers in Region.
+type RegionalPrice struct {
+	GameID   int     `json:"game_id"`
+	Region   string  `json:"region"`
+	Currency string  `json:"currency" binding:"required,len=3"`
+	Price    float64 `json:"price" binding:"required,gt=0"`
+}
+
+// Price is what one customer pays: Amount in their wallet currency, and the
+// same amount in the base currency for reporting.
+type Price struct {
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
+	BaseAmount float64 `json:"base_amount"`
+}
+
+type RegionRequest struct {
+	Region   string `json:"region" binding:"required"`
+	Currency string `json:"currency" binding:"required,len=3"`
+}
+
+type PricingRepository interface {
+	FetchRates(ctx context.Context) ([]ExchangeRate, error)
+	GetRate(ctx context.Context, currency string) (ExchangeRate, error)
+	SaveRate(ctx context.Context, rate *ExchangeRate) error
+	DeleteRate(ctx context.Context, currency string) error
+	FetchRegionalPrices(ctx context.Context, gameID int) ([]RegionalPrice, error)
+	GetRegionalPrice(ctx context.Context, gameID int, region string) (RegionalPrice, error)
+	SaveRegionalPrice(ctx context.Context, price *RegionalPrice) error
+	DeleteRegionalPrice(ctx context.Context, gameID int, region string) error
+}
+
+// PriceResolver turns base-currency prices into what a given customer pays.
+// Callers that are not customers get the base price.
+type PriceResolver interface {
+	BaseCurrency() string
+	ValidateCurrency(ctx context.Context, currency string) error
+	PriceFor(ctx context.Context, gameID int, basePrice float64, userID int) (Price, error)
+	ConvertQuote(ctx context.Context, quote BundleQuote, userID int) (BundleQuote, error)
+}
+
+type PricingUsecase interface {
+	PriceResolver
+	GetRates(ctx context.Context) ([]ExchangeRate, error)
+	SetRate(ctx context.Context, rate *ExchangeRate, actorID int) error
+	DeleteRate(ctx context.Context, currency string) error
+	GetRegionalPrices(ctx context.Context, gameID int) ([]RegionalPrice, error)
+	SetRegionalPrice(ctx context.Context, price *RegionalPrice, requesterID int, role string) error
+	DeleteRegionalPrice(ctx context.Context, gameID int, region string, requesterID int, role string) error
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// MaxTagsPerUser caps how many tags one user can put on a single game.
+const MaxTagsPerUser = 20
+
+var (
+	ErrInvalidTag  = NewError(KindInvalid, "invalid_tag", "tags are 1-32 characters of letters, digits, spaces and dashes")
+	ErrTagNotFound = NewError(KindNotFound, "tag_not_found", "tag not found on this game")
+	ErrTooManyTags = NewError(KindConflict, "too_many_tags", "you have already tagged this game the maximum number of times")
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9 \-]{0,31}$`)
+
+// Tag is a free-form label with the number of games it is applied to.
+type Tag struct {
+	ID    int    `json:"id"`
+	Name  string `json:"tag_name"`
+	Count int    `json:"count"`
+}
+
+// GameTag is a tag on one game; Count is how many users applied it there.
+type GameTag struct {
+	ID          int    `json:"id"`
+	Name        string `json:"tag_name"`
+	Count       int    `json:"count"`
+	AppliedByMe bool   `json:"applied_by_me"`
+}
+
+type TagRequest struct {
+	Name string `json:"tag_name" binding:"required"`
+}
+
+// NormalizeTag lowercases and trims a tag so "RTS " and "rts" are the same tag.
+func NormalizeTag(name string) (string, error) {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	if !tagPattern.MatchString(name) {
+		return "", ErrInvalidTag
+	}
+	return name, nil
+}
+
+type TagRepository interface {
+	Fetch(ctx context.Context, query string, limit int) ([]Tag, error)
+	FetchForGame(ctx context.Context, gameID int, userID int) ([]GameTag, error)
+	CountByUser(ctx context.Context, gameID int, userID int) (int, error)
+	Apply(ctx context.Context, gameID int, userID int, name string) error
+	Remove(ctx context.Context, gameID int, userID int, name string) error
+	RemoveAll(ctx context.Context, gameID int, name string) error
+}
+
+type TagUsecase interface {
+	GetPopular(ctx context.Context, query string, limit int) ([]Tag, error)
+	GetForGame(ctx context.Context, gameID int, viewerUserID int) ([]GameTag, error)
+	Apply(ctx context.Context, gameID int, name string, requesterID int, role string) ([]GameTag, error)
+	Remove(ctx context.Context, gameID int, name string, requesterID int, role string) error
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// ValidationError lists every rejected field with the reason, so clients can
+// fix a request in one round trip.
+type ValidationError struct {
+	Fields map[string]string `json:"fields"`
+}
+
+func NewValidationError() *ValidationError {
+	return &ValidationError{Fields: map[string]string{}}
+}
+
+func (e *ValidationError) Add(field, reason string) {
+	e.Fields[field] = reason
+}
+
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+e.Fields[name])
+	}
+	return "invalid fields: " + strings.Join(parts, "; ")
+}
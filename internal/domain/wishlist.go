@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var ErrWishlistItemNotFound = NewError(KindNotFound, "wishlist_item_not_found", "game is not in your wishlist")
+
+// WishlistItem is a wishlisted game. CurrentPrice is the base price and
+// LocalPrice what the customer would pay.
+type WishlistItem struct {
+	GameID       int       `json:"game_id"`
+	GameName     string    `json:"game_name"`
+	CurrentPrice float64   `json:"current_price"`
+	LocalPrice   Price     `json:"local_price"`
+	StockLevel   int       `json:"stock_level"`
+	AddedAt      time.Time `json:"added_at"`
+}
+
+type WishlistRequest struct {
+	GameID int `json:"game_id" binding:"required"`
+}
+
+// WishlistWatch is a wishlist row with the price and stock the watcher saw
+// last time, next to the game's current values. LastSeenPrice is what the
+// customer was shown in LastSeenCurrency, empty for the base currency; BasePrice
+// is the game's current base price, still to be priced for the customer.
+type WishlistWatch struct {
+	CustomerID       int
+	UserID           int
+	GameID           int
+	GameName         string
+	LastSeenPrice    float64
+	LastSeenCurrency string
+	LastSeenStock    int
+	BasePrice        float64
+	CurrentStock     int
+}
+
+type WishlistRepository interface {
+	Add(ctx context.Context, userID int, gameID int, price Price) error
+	Remove(ctx context.Context, userID int, gameID int) error
+	FetchByUser(ctx context.Context, userID int) ([]WishlistItem, error)
+	FetchWatches(ctx context.Context, afterCustomerID int, afterGameID int, limit int) ([]WishlistWatch, error)
+	UpdateSnapshot(ctx context.Context, customerID int, gameID int, price Price, stock int) error
+}
+
+type WishlistUsecase interface {
+	Add(ctx context.Context, userID int, gameID int) error
+	Remove(ctx context.Context, userID int, gameID int) error
+	GetWishlist(ctx context.Context, userID int) ([]WishlistItem, error)
+	CheckChanges(ctx context.Context) error
+}
@@ -0,0 +1,146 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportBytes = 10 << 20
+
+type CatalogHandler struct {
+	Usecase domain.CatalogUsecase
+}
+
+func NewCatalogHandler(r gin.IRouter, us domain.CatalogUsecase, jwtSecret string) {
+	handler := &CatalogHandler{Usecase: us}
+
+	catalog := r.Group("/games")
+	catalog.Use(middleware.AuthMiddleware(jwtSecret))
+	{
+		catalog.POST("/import", middleware.RoleBlock("publisher"), handler.Import)
+		catalog.GET("/export", middleware.RoleBlock("publisher"), handler.Export)
+	}
+}
+
+// Import takes CSV (text/csv) or JSON lines (application/x-ndjson), or an
+// explicit ?format=csv|jsonl. ?dry_run=true only validates; ?mode picks
+// between all-or-nothing (transactional, the default) and best_effort.
+func (h *CatalogHandler) Import(c *gin.Context) {
+	format := catalogFormat(c.Query("format"), c.GetHeader("Content-Type"))
+	if format == "" {
+		c.Error(domain.Unsupported("send text/csv or application/x-ndjson, or set ?format=csv|jsonl"))
+		return
+	}
+
+	mode := c.DefaultQuery("mode", domain.ImportModeTransactional)
+	if mode != domain.ImportModeTransactional && mode != domain.ImportModeBestEffort {
+		c.Error(domain.Invalid("mode must be transactional or best_effort"))
+		return
+	}
+
+	opts := domain.ImportOptions{
+		Format: format,
+		Mode:   mode,
+		DryRun: c.Query("dry_run") == "true",
+	}
+
+	userID := c.MustGet("user_id").(int)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	report, err := h.Usecase.Import(c.Request.Context(), c.Request.Body, opts, userID)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.Error(domain.ErrCatalogTooLarge)
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	} else if !opts.DryRun && report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, report)
+}
+
+func (h *CatalogHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", domain.CatalogFormatCSV)
+	contentType := "text/csv"
+	switch format {
+	case domain.CatalogFormatCSV:
+	case domain.CatalogFormatJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		c.Error(domain.Invalid("format must be csv or jsonl"))
+		return
+	}
+
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	w := &attachmentWriter{
+		c:           c,
+		contentType: contentType,
+		filename:    fmt.Sprintf("games-%s.%s", time.Now().Format("20060102"), format),
+	}
+	// Once rows have been streamed the error can only be logged.
+	if err := h.Usecase.Export(c.Request.Context(), w, format, userID, role); err != nil {
+		c.Error(err)
+		return
+	}
+	w.start()
+}
+
+// attachmentWriter sends the download headers with the first byte written.
+// The usecase checks access and loads the rows before writing any, so a
+// refusal or a failed query still gets a problem response rather than a
+// file download.
+type attachmentWriter struct {
+	c           *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *attachmentWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.c.Header("Content-Type", w.contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+	w.c.Status(http.StatusOK)
+	w.c.Writer.WriteHeaderNow()
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.c.Writer.Write(p)
+}
+
+func catalogFormat(query, contentType string) string {
+	switch query {
+	case domain.CatalogFormatCSV, domain.CatalogFormatJSONL:
+		return query
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return domain.CatalogFormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/json-lines":
+		return domain.CatalogFormatJSONL
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package delivery
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type exportUsecase struct {
+	domain.CatalogUsecase
+	err error
+}
+
+func (u exportUsecase) Export(ctx context.Context, w io.Writer, format string, requesterID int, role string) error {
+	if u.err != nil {
+		return u.err
+	}
+	_, err := io.WriteString(w, "name,developer\nHollow Pines,Moonlit\n")
+	return err
+}
+
+func TestExportHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1, "role": "publisher"}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		attachment bool
+	}{
+		{name: "rows", status: http.StatusOK, attachment: true},
+		{name: "no publisher profile", err: domain.ErrPublisherNotFound, status: http.StatusForbidden},
+		{name: "database failure", err: context.DeadlineExceeded, status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.ErrorHandler())
+			NewCatalogHandler(r, exportUsecase{err: tt.err}, testSecret)
+
+			req := httptest.NewRequest(http.MethodGet, "/games/export", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status %d, want %d; body %s", w.Code, tt.status, w.Body)
+			}
+			disposition := w.Header().Get("Content-Disposition")
+			if got := strings.HasPrefix(disposition, "attachment"); got != tt.attachment {
+				t.Errorf("Content-Disposition %q, want attachment: %t", disposition, tt.attachment)
+			}
+			wantType := "text/csv"
+			if !tt.attachment {
+				wantType = "application/problem+json"
+			}
+			if got := w.Header().Get("Content-Type"); got != wantType {
+				t.Errorf("Content-Type %q, want %q", got, wantType)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+var errETagMismatch = domain.NewError(domain.KindPrecondition, "etag_mismatch", "If-Match does not match any version of this game")
+
+// gameETag tags body, the serialized g. The id and version lead so If-Match
+// can tell which edit the client saw. Purchases, restocks, media, genre
+// renames, DLC, the 30-day lowest price and the caller's local price all
+// change the body without a new version, so If-None-Match compares a
+// SHA-256 of the body that follows them.
+func gameETag(g domain.Game, body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%d-%d-%x"`, g.ID, g.Version, sum[:16])
+}
+
+// parseIfMatch returns the version a client expects to overwrite. An absent
+// header or "*" yields 0, meaning "whatever is current".
+func parseIfMatch(header string, id int) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		var tagID, version int
+		if _, err := fmt.Sscanf(tag, `"%d-%d`, &tagID, &version); err == nil && tagID == id && version > 0 {
+			return version, nil
+		}
+	}
+	return 0, errETagMismatch
+}
+
+// etagMatches reports whether an If-None-Match header already names etag.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
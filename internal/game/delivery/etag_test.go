@@ -0,0 +1,92 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestGameETag(t *testing.T) {
+	base := domain.Game{ID: 12, Version: 3, StockLevel: 5}
+	restocked := base
+	restocked.StockLevel = 6
+	withDLC := base
+	withDLC.DLC = []domain.DLC{{ID: 40, Name: "Expansion", Price: 9.99}}
+	ownedDLC := base
+	ownedDLC.DLC = []domain.DLC{{ID: 40, Name: "Expansion", Price: 9.99, Owned: true}}
+	localPrice := base
+	localPrice.LocalPrice = &domain.Price{Amount: 21.5, Currency: "EUR", BaseAmount: 19.99}
+	cheaper := base
+	cheaper.LowestPrice30d = 4.99
+
+	tag := func(g domain.Game) string {
+		body, err := json.Marshal(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gameETag(g, body)
+	}
+
+	if tag(base) != tag(base) {
+		t.Errorf("the same body got different tags")
+	}
+
+	shaped := regexp.MustCompile(`^"12-3-[0-9a-f]{32}"$`)
+	tags := map[string]string{}
+	variants := map[string]domain.Game{
+		"base": base, "restocked": restocked, "dlc": withDLC, "owned dlc": ownedDLC,
+		"local price": localPrice, "lowest price": cheaper,
+	}
+	for name, g := range variants {
+		got := tag(g)
+		if !shaped.MatchString(got) {
+			t.Errorf("%s: got %s, want \"12-3-<hash>\"", name, got)
+		}
+		if other, ok := tags[got]; ok {
+			t.Errorf("%s: %s is shared with %s", name, got, other)
+		}
+		tags[got] = name
+
+		// If-Match only looks at the id and version.
+		if v, err := parseIfMatch(got, 12); err != nil || v != 3 {
+			t.Errorf("%s: parseIfMatch(%s) = %d, %v", name, got, v, err)
+		}
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int
+		wantErr bool
+	}{
+		{header: "", want: 0},
+		{header: "  ", want: 0},
+		{header: "*", want: 0},
+		{header: `"12-3"`, want: 3},
+		{header: `W/"12-3"`, want: 3},
+		{header: `"12-3-0a1b2c3d"`, want: 3},
+		{header: `"7-1", "12-4"`, want: 4},
+		{header: `"7-1"`, wantErr: true},
+		{header: `"12-0"`, wantErr: true},
+		{header: `"12"`, wantErr: true},
+		{header: `12-3`, wantErr: true},
+		{header: `"abc"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := parseIfMatch(tt.header, 12)
+			if tt.wantErr {
+				if !errors.Is(err, errETagMismatch) {
+					t.Fatalf("err = %v, want %v", err, errETagMismatch)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
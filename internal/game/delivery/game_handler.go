@@ -3,8 +3,14 @@ package delivery
 import (
 	"cool-games/internal/domain"
 	"cool-games/internal/middleware"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,33 +19,42 @@ type GameHandler struct {
 	GameUsecase domain.GameUsecase
 }
 
-func NewGameHandler(r *gin.Engine, us domain.GameUsecase, jwtSecret string) {
+func NewGameHandler(r gin.IRouter, us domain.GameUsecase, jwtSecret string) {
 	handler := &GameHandler{GameUsecase: us}
 
 	r.GET("/games", handler.Fetch)
-	r.GET("/games/:id", handler.GetByID)
+	r.GET("/games/:id", middleware.OptionalAuth(jwtSecret), handler.GetByID)
 
 	protected := r.Group("/games")
 	protected.Use(middleware.AuthMiddleware(jwtSecret))
 	{
 		protected.GET("/my-games", middleware.RoleBlock("publisher"), handler.GetMyGames)
+		protected.GET("/trash", middleware.RoleBlock("publisher", "admin"), handler.GetTrash)
+		protected.POST("/:id/restore", middleware.RoleBlock("publisher", "admin"), handler.Restore)
 		protected.POST("", middleware.RoleBlock("publisher"), handler.Create)
 		protected.PUT("/:id", middleware.RoleBlock("publisher"), handler.Update)
+		protected.PATCH("/:id", middleware.RoleBlock("publisher"), handler.Patch)
+		protected.GET("/:id/audit", middleware.RoleBlock("publisher"), handler.GetAuditTrail)
+		protected.GET("/:id/price-history", middleware.RoleBlock("publisher"), handler.GetPriceHistory)
 		protected.DELETE("/:id", middleware.RoleBlock("publisher", "admin"), handler.Delete)
 		protected.PATCH("/:id/restock", middleware.RoleBlock("publisher"), handler.Restock)
+		protected.GET("/:id/stock-rule", middleware.RoleBlock("publisher"), handler.GetStockRule)
+		protected.PUT("/:id/stock-rule", middleware.RoleBlock("publisher"), handler.SetStockRule)
+		protected.GET("/:id/stock-history", middleware.RoleBlock("publisher"), handler.GetStockHistory)
+		protected.GET("/:id/stock-history/daily", middleware.RoleBlock("publisher"), handler.GetDailyStockSummary)
 	}
 }
 
 func (h *GameHandler) Create(c *gin.Context) {
 	var g domain.Game
 	if err := c.ShouldBindJSON(&g); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(domain.ErrInvalidBody.Wrap(err))
 		return
 	}
 
 	userID := c.MustGet("user_id").(int)
 	if err := h.GameUsecase.Create(c.Request.Context(), &g, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, g)
@@ -52,38 +67,133 @@ func (h *GameHandler) Update(c *gin.Context) {
 
 	var g domain.Game
 	if err := c.ShouldBindJSON(&g); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(domain.ErrInvalidBody.Wrap(err))
 		return
 	}
 
-	if err := h.GameUsecase.Update(c.Request.Context(), id, &g, userID, role); err != nil {
-		status := http.StatusInternalServerError
-		if err == domain.ErrUnauthorizedAction { status = http.StatusForbidden }
-		if err == domain.ErrGameNotFound { status = http.StatusNotFound }
-		c.JSON(status, gin.H{"error": err.Error()})
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"), id)
+	if err != nil {
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, g)
+
+	if err := h.GameUsecase.Update(c.Request.Context(), id, &g, userID, role, expectedVersion); err != nil {
+		c.Error(err)
+		return
+	}
+	renderGame(c, g, false)
 }
 
-func (h *GameHandler) GetByID(c *gin.Context) {
+const maxPatchBytes = 1 << 20
+
+// Patch accepts an RFC 7396 merge patch (application/merge-patch+json, plain
+// application/json is tolerated) and changes only the fields it mentions.
+func (h *GameHandler) Patch(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		c.Error(domain.Unsupported("use Content-Type application/merge-patch+json"))
+		return
+	}
+
+	patch, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPatchBytes))
+	if err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.GameUsecase.Patch(c.Request.Context(), id, patch, userID, role, expectedVersion)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	renderGame(c, res, false)
+}
+
+func (h *GameHandler) GetAuditTrail(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	res, err := h.GameUsecase.GetAuditTrail(c.Request.Context(), id, userID, role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GameHandler) GetPriceHistory(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	res, err := h.GameUsecase.GetByID(c.Request.Context(), id)
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	res, err := h.GameUsecase.GetPriceHistory(c.Request.Context(), id, userID, role)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
 }
 
+func (h *GameHandler) GetByID(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	viewerID := c.GetInt("user_id")
+	res, err := h.GameUsecase.GetByID(c.Request.Context(), id, viewerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Vary", "Authorization")
+	renderGame(c, res, true)
+}
+
+// renderGame sends g with an ETag over the exact bytes sent. When
+// conditional, a request whose If-None-Match already names that tag gets a
+// 304 instead.
+func renderGame(c *gin.Context, g domain.Game, conditional bool) {
+	body, err := json.Marshal(g)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	etag := gameETag(g, body)
+	c.Header("ETag", etag)
+	if inm := c.GetHeader("If-None-Match"); conditional && inm != "" && etagMatches(inm, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
 func (h *GameHandler) Fetch(c *gin.Context) {
-	search := c.Query("search")
-	minPrice, _ := strconv.ParseFloat(c.DefaultQuery("min_price", "0"), 64)
-	maxPrice, _ := strconv.ParseFloat(c.DefaultQuery("max_price", "0"), 64)
+	filter := domain.GameFilter{
+		Search:          c.Query("search"),
+		AgeRatingSystem: c.Query("age_rating_system"),
+		AgeRating:       c.Query("age_rating"),
+		Language:        c.Query("language"),
+		Genre:           c.Query("genre"),
+		Tag:             strings.ToLower(strings.TrimSpace(c.Query("tag"))),
+	}
+	filter.MinPrice, _ = strconv.ParseFloat(c.DefaultQuery("min_price", "0"), 64)
+	filter.MaxPrice, _ = strconv.ParseFloat(c.DefaultQuery("max_price", "0"), 64)
+	filter.MaxAge, _ = strconv.Atoi(c.DefaultQuery("max_age", "0"))
 
-	res, err := h.GameUsecase.GetAll(c.Request.Context(), search, minPrice, maxPrice)
+	res, err := h.GameUsecase.GetAll(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -95,7 +205,7 @@ func (h *GameHandler) Delete(c *gin.Context) {
 	userID := c.MustGet("user_id").(int)
 
 	if err := h.GameUsecase.Delete(c.Request.Context(), id, userID, role); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -105,7 +215,7 @@ func (h *GameHandler) GetMyGames(c *gin.Context) {
 	userID := c.MustGet("user_id").(int)
 	res, err := h.GameUsecase.GetByPublisher(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -116,12 +226,147 @@ func (h *GameHandler) Restock(c *gin.Context) {
 	userID := c.MustGet("user_id").(int)
 	var req domain.RestockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(domain.ErrInvalidBody.Wrap(err))
 		return
 	}
-	if err := h.GameUsecase.Restock(c.Request.Context(), id, userID, req.Amount); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := h.GameUsecase.Restock(c.Request.Context(), id, userID, req); err != nil {
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Stock updated successfully"})
+}
+
+func (h *GameHandler) GetStockRule(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	rule, err := h.GameUsecase.GetStockRule(c.Request.Context(), id, userID, role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *GameHandler) SetStockRule(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	var rule domain.StockRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+	rule.GameID = id
+
+	if err := h.GameUsecase.SetStockRule(c.Request.Context(), &rule, userID, role); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *GameHandler) GetStockHistory(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	filter, err := parseStockHistoryFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.GameUsecase.GetStockHistory(c.Request.Context(), id, userID, role, filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GameHandler) GetDailyStockSummary(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	filter, err := parseStockHistoryFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.GameUsecase.GetDailyStockSummary(c.Request.Context(), id, userID, role, filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// parseStockHistoryFilter reads from/to (YYYY-MM-DD or RFC 3339, with a plain
+// "to" date covering that whole day) and page/page_size.
+func parseStockHistoryFilter(c *gin.Context) (domain.StockHistoryFilter, error) {
+	filter := domain.StockHistoryFilter{Page: 1, PageSize: 50}
+
+	if v := c.Query("from"); v != "" {
+		from, _, err := parseDateParam(v)
+		if err != nil {
+			return filter, domain.ErrInvalidQuery.Wrap(fmt.Errorf("from: %w", err))
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, dateOnly, err := parseDateParam(v)
+		if err != nil {
+			return filter, domain.ErrInvalidQuery.Wrap(fmt.Errorf("to: %w", err))
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	if page, err := strconv.Atoi(c.DefaultQuery("page", "1")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if size, err := strconv.Atoi(c.DefaultQuery("page_size", "50")); err == nil && size > 0 && size <= 200 {
+		filter.PageSize = size
+	}
+	return filter, nil
+}
+
+func parseDateParam(v string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
+}
+
+func (h *GameHandler) GetTrash(c *gin.Context) {
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	res, err := h.GameUsecase.GetTrash(c.Request.Context(), userID, role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GameHandler) Restore(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	res, err := h.GameUsecase.Restore(c.Request.Context(), id, userID, role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
 }
\ No newline at end of file
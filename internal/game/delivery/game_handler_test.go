@@ -0,0 +1,93 @@
+package delivery
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+// stockOnlyUsecase serves one game and restocks it the way the repository
+// does: stock_level moves, version does not.
+type stockOnlyUsecase struct {
+	domain.GameUsecase
+	game domain.Game
+}
+
+func (u *stockOnlyUsecase) GetByID(ctx context.Context, id int, viewerUserID int) (domain.Game, error) {
+	if id != u.game.ID {
+		return domain.Game{}, domain.ErrGameNotFound
+	}
+	return u.game, nil
+}
+
+func (u *stockOnlyUsecase) Restock(ctx context.Context, gameID int, requesterID int, req domain.RestockRequest) error {
+	u.game.StockLevel += req.Amount
+	return nil
+}
+
+func TestGetByIDRevalidatesAfterRestock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	us := &stockOnlyUsecase{game: domain.Game{ID: 7, Name: "Tetris", Price: 9.99, StockLevel: 2, Version: 4}}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	NewGameHandler(r, us, testSecret)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1, "role": "publisher"}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/games/7", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	if first.Code != http.StatusOK {
+		t.Fatalf("GET: status %d, body %s", first.Code, first.Body)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("GET: no ETag")
+	}
+	if w := get(etag); w.Code != http.StatusNotModified {
+		t.Fatalf("GET with the current tag: status %d, want 304", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/games/7/restock", strings.NewReader(`{"amount": 3}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("restock: status %d, body %s", w.Code, w.Body)
+	}
+
+	after := get(etag)
+	if after.Code != http.StatusOK {
+		t.Fatalf("GET after restock with the old tag: status %d, want 200", after.Code)
+	}
+	if !strings.Contains(after.Body.String(), `"stock_level":5`) {
+		t.Errorf("GET after restock: body %s, want stock_level 5", after.Body)
+	}
+	if got := after.Header().Get("ETag"); got == etag {
+		t.Errorf("ETag %s did not change with the stock level", got)
+	}
+	if v, err := parseIfMatch(after.Header().Get("ETag"), 7); err != nil || v != 4 {
+		t.Errorf("If-Match version after restock = %d, %v, want 4", v, err)
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
+)
+
+func insertAuditEntry(ctx context.Context, tx *sql.Tx, a *domain.GameAuditEntry) error {
+	changes, err := json.Marshal(a.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO game_audit_log (game_id, actor_user_id, action, changed_fields, changes)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return tx.QueryRowContext(ctx, query, a.GameID, a.ActorUserID, a.Action, pq.Array(a.ChangedFields), changes).
+		Scan(&a.ID, &a.CreatedAt)
+}
+
+func (m *psqlGameRepository) FetchAuditTrail(ctx context.Context, gameID int) ([]domain.GameAuditEntry, error) {
+	query := `
+		SELECT id, game_id, actor_user_id, action, changed_fields, changes, created_at
+		FROM game_audit_log
+		WHERE game_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := m.db.QueryContext(ctx, query, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.GameAuditEntry{}
+	for rows.Next() {
+		var a domain.GameAuditEntry
+		var changes []byte
+		err := rows.Scan(&a.ID, &a.GameID, &a.ActorUserID, &a.Action, pq.Array(&a.ChangedFields), &changes, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &a.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, a)
+	}
+	return entries, rows.Err()
+}
+
+func (m *psqlGameRepository) FetchPriceHistory(ctx context.Context, gameID int) ([]domain.PriceHistoryEntry, error) {
+	query := `
+		SELECT id, game_id, price, actor_user_id, changed_at
+		FROM game_price_history
+		WHERE game_id = $1
+		ORDER BY changed_at DESC, id DESC`
+
+	rows, err := m.db.QueryContext(ctx, query, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.PriceHistoryEntry{}
+	for rows.Next() {
+		var e domain.PriceHistoryEntry
+		if err := rows.Scan(&e.ID, &e.GameID, &e.Price, &e.ActorUserID, &e.ChangedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// GetGenresByName resolves genre names case-insensitively. The map is keyed
+// by the lower-cased name; unknown names are simply missing from it.
+func (m *psqlGameRepository) GetGenresByName(ctx context.Context, names []string) (map[string]domain.Genre, error) {
+	lowered := make([]string, 0, len(names))
+	for _, n := range names {
+		lowered = append(lowered, strings.ToLower(n))
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT id, genre_name FROM genres WHERE LOWER(genre_name) = ANY($1)`, pq.Array(lowered))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := map[string]domain.Genre{}
+	for rows.Next() {
+		var g domain.Genre
+		if err := rows.Scan(&g.ID, &g.Name); err != nil {
+			return nil, err
+		}
+		res[strings.ToLower(g.Name)] = g
+	}
+	return res, rows.Err()
+}
+
+func (m *psqlGameRepository) GetDeveloperNames(ctx context.Context, ids []int) (map[int]string, error) {
+	ids64 := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		ids64 = append(ids64, int64(id))
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT id, developer_name FROM developers WHERE id = ANY($1)`, pq.Array(ids64))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := map[int]string{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		res[id] = name
+	}
+	return res, rows.Err()
+}
+
+// GetDeveloperIDsByName resolves developer names case-insensitively. Names
+// are not unique, so a name may map to several ids.
+func (m *psqlGameRepository) GetDeveloperIDsByName(ctx context.Context, names []string) (map[string][]int, error) {
+	lowered := make([]string, 0, len(names))
+	for _, n := range names {
+		lowered = append(lowered, strings.ToLower(n))
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT id, LOWER(developer_name) FROM developers WHERE LOWER(developer_name) = ANY($1) ORDER BY id`, pq.Array(lowered))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := map[string][]int{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		res[name] = append(res[name], id)
+	}
+	return res, rows.Err()
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"cool-games/internal/domain"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type psqlGameRepository struct {
@@ -17,9 +20,101 @@ func NewPsqlGameRepository(db *sql.DB) domain.GameRepository {
 	return &psqlGameRepository{db}
 }
 
+func NewPsqlCatalogRepository(db *sql.DB) domain.CatalogRepository {
+	return &psqlGameRepository{db}
+}
+
+// gameColumns is the column list every game query selects, in the order
+// scanGame expects. It must be selected FROM games without an alias.
+const gameColumns = `id, publisher_id, parent_game_id, developer_id, game_name, price, ` + lowestPrice30dColumn + `, stock_level,
+	short_description, description, min_requirements, recommended_requirements,
+	age_rating_system, age_rating, min_age, languages,
+	release_date, version, deleted_at`
+
+// lowestPrice30dColumn is the lowest price in effect at any point of the
+// last 30 days: the current price, every price set within the window and the
+// price that was already in effect when the window opened.
+const lowestPrice30dColumn = `LEAST(price,
+	(SELECT MIN(ph.price) FROM game_price_history ph
+	 WHERE ph.game_id = games.id AND ph.changed_at >= NOW() - INTERVAL '30 days'),
+	(SELECT ph.price FROM game_price_history ph
+	 WHERE ph.game_id = games.id AND ph.changed_at < NOW() - INTERVAL '30 days'
+	 ORDER BY ph.changed_at DESC LIMIT 1))`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanGame(row rowScanner) (domain.Game, error) {
+	var g domain.Game
+	var releaseDate sql.NullTime
+	var minReq, recReq []byte
+	err := row.Scan(&g.ID, &g.PublisherID, &g.ParentGameID, &g.DeveloperID, &g.Name, &g.Price, &g.LowestPrice30d, &g.StockLevel,
+		&g.ShortDescription, &g.Description, &minReq, &recReq,
+		&g.AgeRatingSystem, &g.AgeRating, &g.MinimumAge, pq.Array(&g.Languages),
+		&releaseDate, &g.Version, &g.DeletedAt)
+	if err != nil {
+		return g, err
+	}
+	if releaseDate.Valid {
+		g.ReleaseDate = releaseDate.Time
+	}
+	if g.MinRequirements, err = parseRequirements(minReq); err != nil {
+		return g, err
+	}
+	g.RecommendedRequirements, err = parseRequirements(recReq)
+	return g, err
+}
+
+func parseRequirements(raw []byte) (*domain.SystemRequirements, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var req domain.SystemRequirements
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// requirementsValue stores nil requirements as NULL instead of "null".
+func requirementsValue(req *domain.SystemRequirements) (interface{}, error) {
+	if req == nil {
+		return nil, nil
+	}
+	return json.Marshal(req)
+}
+
+// detailArgs are the values of the detail columns, in the order
+// short_description, description, min_requirements, recommended_requirements,
+// age_rating_system, age_rating, min_age, languages.
+func detailArgs(g *domain.Game) ([]interface{}, error) {
+	minReq, err := requirementsValue(g.MinRequirements)
+	if err != nil {
+		return nil, err
+	}
+	recReq, err := requirementsValue(g.RecommendedRequirements)
+	if err != nil {
+		return nil, err
+	}
+	languages := g.Languages
+	if languages == nil {
+		languages = []string{}
+	}
+	return []interface{}{
+		g.ShortDescription, g.Description, minReq, recReq,
+		g.AgeRatingSystem, g.AgeRating, domain.MinimumAge(g.AgeRatingSystem, g.AgeRating), pq.Array(languages),
+	}, nil
+}
+
+// nullableDate stores a zero time as NULL rather than 0001-01-01.
+func nullableDate(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
 func (m *psqlGameRepository) getGenresForGame(ctx context.Context, gameID int) ([]domain.Genre, error) {
     query := `
-        SELECT g.id, g.genre_name 
+        SELECT g.id, g.genre_name, g.slug, g.parent_id 
         FROM genres g 
         JOIN game_genres gg ON g.id = gg.genre_id 
         WHERE gg.game_id = $1`
@@ -33,7 +128,7 @@ func (m *psqlGameRepository) getGenresForGame(ctx context.Context, gameID int) (
     genres := []domain.Genre{}
     for rows.Next() {
         var gen domain.Genre
-        if err := rows.Scan(&gen.ID, &gen.Name); err != nil {
+        if err := rows.Scan(&gen.ID, &gen.Name, &gen.Slug, &gen.ParentID); err != nil {
             return nil, err
         }
         genres = append(genres, gen)
@@ -41,6 +136,32 @@ func (m *psqlGameRepository) getGenresForGame(ctx context.Context, gameID int) (
     return genres, nil
 }
 
+func (m *psqlGameRepository) getMediaForGame(ctx context.Context, gameID int) ([]domain.GameMedia, error) {
+	query := `
+		SELECT id, game_id, kind, url, thumbnail_url, content_type, size_bytes, width, height, created_at
+		FROM game_media
+		WHERE game_id = $1
+		ORDER BY CASE kind WHEN 'cover' THEN 0 ELSE 1 END, created_at`
+
+	rows, err := m.db.QueryContext(ctx, query, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	media := []domain.GameMedia{}
+	for rows.Next() {
+		var md domain.GameMedia
+		err := rows.Scan(&md.ID, &md.GameID, &md.Kind, &md.URL, &md.ThumbnailURL, &md.ContentType,
+			&md.SizeBytes, &md.Width, &md.Height, &md.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, md)
+	}
+	return media, rows.Err()
+}
+
 func (m *psqlGameRepository) Store(ctx context.Context, g *domain.Game) error {
 	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -48,39 +169,144 @@ func (m *psqlGameRepository) Store(ctx context.Context, g *domain.Game) error {
 	}
 	defer tx.Rollback()
 
-	query := `INSERT INTO games (publisher_id, developer_id, game_name, price, stock_level) 
-              VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	if err := tx.QueryRowContext(ctx, query, g.PublisherID, g.DeveloperID, g.Name, g.Price, g.StockLevel).Scan(&g.ID); err != nil {
+	if err := storeGame(ctx, tx, g); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// StoreMany inserts all games in one transaction: either every game is
+// created or none is.
+func (m *psqlGameRepository) StoreMany(ctx context.Context, games []*domain.Game) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, g := range games {
+		if err := storeGame(ctx, tx, g); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// storeError turns the constraint violations an insert can still hit after
+// validation, such as a developer or genre deleted in the meantime, into
+// domain errors. Anything else stays an internal error.
+func storeError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case "23503": // foreign_key_violation
+		switch pqErr.Constraint {
+		case "games_developer_id_fkey":
+			return domain.ErrDeveloperNotFound
+		case "game_genres_genre_id_fkey":
+			return domain.ErrGenreNotFound
+		case "games_parent_game_id_fkey":
+			return domain.ErrGameNotFound
+		}
+	case "22001", "22003": // string_data_right_truncation, numeric_value_out_of_range
+		return domain.ErrGameOutOfRange
+	}
+	return err
+}
+
+func storeGame(ctx context.Context, tx *sql.Tx, g *domain.Game) error {
+	details, err := detailArgs(g)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO games (publisher_id, developer_id, game_name, price, stock_level, release_date,
+                short_description, description, min_requirements, recommended_requirements,
+                age_rating_system, age_rating, min_age, languages, parent_game_id) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, version`
+	args := append([]interface{}{g.PublisherID, g.DeveloperID, g.Name, g.Price, g.StockLevel, nullableDate(g.ReleaseDate)}, details...)
+	args = append(args, g.ParentGameID)
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&g.ID, &g.Version); err != nil {
+		return storeError(err)
+	}
+
+	// The starting price opens the price history, credited to the publisher.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO game_price_history (game_id, price, actor_user_id)
+		SELECT id, price, (SELECT user_id FROM publishers WHERE id = publisher_id) FROM games WHERE id = $1`, g.ID)
+	if err != nil {
 		return err
 	}
 
 	for _, gen := range g.Genres {
 		_, err := tx.ExecContext(ctx, "INSERT INTO game_genres (game_id, genre_id) VALUES ($1, $2)", g.ID, gen.ID)
 		if err != nil {
-			return err
+			return storeError(err)
 		}
 	}
-	return tx.Commit()
+	return nil
 }
 
-func (m *psqlGameRepository) Fetch(ctx context.Context, search string, minPrice, maxPrice float64) ([]domain.Game, error) {
-	query := `SELECT id, publisher_id, developer_id, game_name, price, stock_level FROM games WHERE deleted_at IS NULL`
+func (m *psqlGameRepository) Fetch(ctx context.Context, filter domain.GameFilter) ([]domain.Game, error) {
+	query := `SELECT ` + gameColumns + ` FROM games WHERE deleted_at IS NULL`
 	args := []interface{}{}
 	argCount := 1
 
-	if search != "" {
+	if filter.Search != "" {
 		query += fmt.Sprintf(" AND game_name ILIKE $%d", argCount)
-		args = append(args, "%"+search+"%")
+		args = append(args, "%"+filter.Search+"%")
 		argCount++
 	}
-	if minPrice > 0 {
+	if filter.MinPrice > 0 {
 		query += fmt.Sprintf(" AND price >= $%d", argCount)
-		args = append(args, minPrice)
+		args = append(args, filter.MinPrice)
 		argCount++
 	}
-	if maxPrice > 0 {
+	if filter.MaxPrice > 0 {
 		query += fmt.Sprintf(" AND price <= $%d", argCount)
-		args = append(args, maxPrice)
+		args = append(args, filter.MaxPrice)
+		argCount++
+	}
+	if filter.AgeRatingSystem != "" {
+		query += fmt.Sprintf(" AND age_rating_system = $%d", argCount)
+		args = append(args, filter.AgeRatingSystem)
+		argCount++
+	}
+	if filter.AgeRating != "" {
+		query += fmt.Sprintf(" AND age_rating = $%d", argCount)
+		args = append(args, filter.AgeRating)
+		argCount++
+	}
+	if filter.MaxAge > 0 {
+		query += fmt.Sprintf(" AND min_age <= $%d", argCount)
+		args = append(args, filter.MaxAge)
+		argCount++
+	}
+	if filter.Language != "" {
+		query += fmt.Sprintf(" AND $%d = ANY(languages)", argCount)
+		args = append(args, filter.Language)
+		argCount++
+	}
+	if filter.Genre != "" {
+		// A parent genre also matches games filed under any of its subgenres.
+		query += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM game_genres gg WHERE gg.game_id = games.id AND gg.genre_id IN (
+				WITH RECURSIVE subtree AS (
+					SELECT id FROM genres WHERE id::text = $%d OR slug = $%d
+					UNION ALL
+					SELECT g.id FROM genres g JOIN subtree s ON g.parent_id = s.id
+				)
+				SELECT id FROM subtree))`, argCount, argCount)
+		args = append(args, filter.Genre)
+		argCount++
+	}
+	if filter.Tag != "" {
+		query += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM game_tags gt JOIN tags t ON t.id = gt.tag_id
+			WHERE gt.game_id = games.id AND t.tag_name = $%d)`, argCount)
+		args = append(args, filter.Tag)
 		argCount++
 	}
 
@@ -92,40 +318,106 @@ func (m *psqlGameRepository) Fetch(ctx context.Context, search string, minPrice,
 
 	var res []domain.Game
     for rows.Next() {
-        var g domain.Game
-        err := rows.Scan(&g.ID, &g.PublisherID, &g.DeveloperID, &g.Name, &g.Price, &g.StockLevel)
+        g, err := scanGame(rows)
         if err != nil { return nil, err }
         
-        g.Genres, _ = m.getGenresForGame(ctx, g.ID)
+        if err := m.loadGameRelations(ctx, &g); err != nil { return nil, err }
         res = append(res, g)
     }
-    return res, nil
+    return res, rows.Err()
 }
 
 func (m *psqlGameRepository) GetByID(ctx context.Context, id int) (domain.Game, error) {
-	query := `SELECT id, publisher_id, developer_id, game_name, price, stock_level 
-              FROM games WHERE id = $1 AND deleted_at IS NULL`
-	var g domain.Game
-	err := m.db.QueryRowContext(ctx, query, id).Scan(
-		&g.ID, &g.PublisherID, &g.DeveloperID, &g.Name, &g.Price, &g.StockLevel,
-	)
-	if err != nil {
+	query := `SELECT ` + gameColumns + ` FROM games WHERE id = $1 AND deleted_at IS NULL`
+	g, err := scanGame(m.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
 		return domain.Game{}, domain.ErrGameNotFound
 	}
+	if err != nil {
+		return domain.Game{}, err
+	}
 
-	g.Genres, _ = m.getGenresForGame(ctx, g.ID)
+	if err := m.loadGameRelations(ctx, &g); err != nil {
+		return domain.Game{}, err
+	}
     return g, nil
 }
 
-func (m *psqlGameRepository) Update(ctx context.Context, g *domain.Game) error {
+// loadGameRelations fills in the genres and media of a game just scanned.
+func (m *psqlGameRepository) loadGameRelations(ctx context.Context, g *domain.Game) error {
+	var err error
+	if g.Genres, err = m.getGenresForGame(ctx, g.ID); err != nil {
+		return err
+	}
+	g.Media, err = m.getMediaForGame(ctx, g.ID)
+	return err
+}
+
+// FetchDLC lists the live add-ons of a base game. Owned reflects the library
+// of the customer behind userID; pass 0 for anonymous callers.
+func (m *psqlGameRepository) FetchDLC(ctx context.Context, baseGameID int, userID int) ([]domain.DLC, error) {
+	query := `
+		SELECT g.id, g.game_name, g.price, g.stock_level,
+		       EXISTS (SELECT 1 FROM customer_game_library cgl
+		               JOIN customers c ON c.id = cgl.customer_id
+		               WHERE cgl.game_id = g.id AND c.user_id = $2)
+		FROM games g
+		WHERE g.parent_game_id = $1 AND g.deleted_at IS NULL
+		ORDER BY g.id`
+
+	rows, err := m.db.QueryContext(ctx, query, baseGameID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.DLC
+	for rows.Next() {
+		var d domain.DLC
+		if err := rows.Scan(&d.ID, &d.Name, &d.Price, &d.StockLevel, &d.Owned); err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+// Update writes the editable columns and, when audit is given, the audit row
+// in the same transaction.
+func (m *psqlGameRepository) Update(ctx context.Context, g *domain.Game, audit *domain.GameAuditEntry) error {
 	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	query := `UPDATE games SET developer_id=$1, game_name=$2, price=$3, stock_level=$4, updated_at=NOW() WHERE id=$5`
-	_, err = tx.ExecContext(ctx, query, g.DeveloperID, g.Name, g.Price, g.StockLevel, g.ID)
+	// stock_level is deliberately left alone: it moves through UpdateStock and
+	// purchases, and an edit must never put back a stale value.
+	details, err := detailArgs(g)
+	if err != nil {
+		return err
+	}
+
+	var previousPrice float64
+	err = tx.QueryRowContext(ctx, "SELECT price FROM games WHERE id = $1 FOR UPDATE", g.ID).Scan(&previousPrice)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrGameNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE games SET developer_id=$1, game_name=$2, price=$3,
+                short_description=$6, description=$7, min_requirements=$8, recommended_requirements=$9,
+                age_rating_system=$10, age_rating=$11, min_age=$12, languages=$13,
+                parent_game_id=$14, version=version+1, updated_at=NOW()
+              WHERE id=$4 AND version=$5 AND deleted_at IS NULL RETURNING version`
+	args := append([]interface{}{g.DeveloperID, g.Name, g.Price, g.ID, g.Version}, details...)
+	args = append(args, g.ParentGameID)
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&g.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrVersionConflict
+	}
 	if err != nil {
 		return err
 	}
@@ -142,16 +434,34 @@ func (m *psqlGameRepository) Update(ctx context.Context, g *domain.Game) error {
 		}
 	}
 
+	if audit != nil {
+		if err := insertAuditEntry(ctx, tx, audit); err != nil {
+			return err
+		}
+	}
+
+	// Compare in SQL so the stored NUMERIC decides, not the float sent in.
+	var actor *int
+	if audit != nil {
+		actor = &audit.ActorUserID
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO game_price_history (game_id, price, actor_user_id)
+		SELECT id, price, $2 FROM games WHERE id = $1 AND price <> $3`, g.ID, actor, previousPrice)
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
 func (m *psqlGameRepository) Delete(ctx context.Context, id int) error {
-	query := `UPDATE games SET deleted_at = $1 WHERE id = $2`
+	query := `UPDATE games SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
 	_, err := m.db.ExecContext(ctx, query, time.Now(), id)
 	return err
 }
 
-func (m *psqlGameRepository) UpdateStock(ctx context.Context, gameID int, change int) error {
+func (m *psqlGameRepository) UpdateStock(ctx context.Context, sc domain.StockChange) error {
     tx, err := m.db.BeginTx(ctx, nil)
     if err != nil {
         return err
@@ -159,18 +469,18 @@ func (m *psqlGameRepository) UpdateStock(ctx context.Context, gameID int, change
     defer tx.Rollback()
 
     query := `UPDATE games SET stock_level = stock_level + $1 WHERE id = $2 AND stock_level + $1 >= 0`
-    res, err := tx.ExecContext(ctx, query, change, gameID)
+    res, err := tx.ExecContext(ctx, query, sc.Change, sc.GameID)
     if err != nil {
         return err
     }
     
     rows, _ := res.RowsAffected()
     if rows == 0 {
-        return errors.New("could not update stock (insufficient stock or game not found)")
+        return domain.ErrOutOfStock.WithDetails(map[string]interface{}{"game_id": sc.GameID})
     }
 
-    historyQuery := `INSERT INTO game_quantity_history (game_id, change_amount, transaction_date) VALUES ($1, $2, NOW())`
-    if _, err := tx.ExecContext(ctx, historyQuery, gameID, change); err != nil {
+    historyQuery := `INSERT INTO game_quantity_history (game_id, change_amount, reason, actor_user_id, transaction_date) VALUES ($1, $2, $3, $4, NOW())`
+    if _, err := tx.ExecContext(ctx, historyQuery, sc.GameID, sc.Change, sc.Reason, sc.ActorUserID); err != nil {
         return err
     }
 
@@ -178,8 +488,7 @@ func (m *psqlGameRepository) UpdateStock(ctx context.Context, gameID int, change
 }
 
 func (m *psqlGameRepository) FetchByPublisher(ctx context.Context, publisherID int) ([]domain.Game, error) {
-	query := `SELECT id, publisher_id, developer_id, game_name, price, stock_level 
-              FROM games WHERE publisher_id = $1 AND deleted_at IS NULL`
+	query := `SELECT ` + gameColumns + ` FROM games WHERE publisher_id = $1 AND deleted_at IS NULL`
 	rows, err := m.db.QueryContext(ctx, query, publisherID)
 	if err != nil {
 		return nil, err
@@ -188,17 +497,56 @@ func (m *psqlGameRepository) FetchByPublisher(ctx context.Context, publisherID i
 
 	var res []domain.Game
 	for rows.Next() {
-		var g domain.Game
-		rows.Scan(&g.ID, &g.PublisherID, &g.DeveloperID, &g.Name, &g.Price, &g.StockLevel)
-		g.Genres, _ = m.getGenresForGame(ctx, g.ID)
+		g, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.loadGameRelations(ctx, &g); err != nil {
+			return nil, err
+		}
 		res = append(res, g)
 	}
-	return res, nil
+	return res, rows.Err()
 }
 
 func (m *psqlGameRepository) GetPublisherIDByUserID(ctx context.Context, userID int) (int, error) {
 	var id int
 	query := `SELECT id FROM publishers WHERE user_id = $1`
 	err := m.db.QueryRowContext(ctx, query, userID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrPublisherNotFound
+	}
 	return id, err
-}
\ No newline at end of file
+}
+
+func (m *psqlGameRepository) GetPublisherUserID(ctx context.Context, publisherID int) (int, error) {
+	var userID int
+	query := `SELECT user_id FROM publishers WHERE id = $1`
+	err := m.db.QueryRowContext(ctx, query, publisherID).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrGamePublisherNotFound
+	}
+	return userID, err
+}
+
+func (m *psqlGameRepository) GetStockRule(ctx context.Context, gameID int) (domain.StockRule, error) {
+	rule := domain.StockRule{GameID: gameID}
+	query := `SELECT low_stock_threshold, auto_restock_amount FROM game_stock_rules WHERE game_id = $1`
+	err := m.db.QueryRowContext(ctx, query, gameID).Scan(&rule.LowStockThreshold, &rule.AutoRestockAmount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rule, nil
+	}
+	return rule, err
+}
+
+func (m *psqlGameRepository) SaveStockRule(ctx context.Context, rule *domain.StockRule) error {
+	query := `
+		INSERT INTO game_stock_rules (game_id, low_stock_threshold, auto_restock_amount, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (game_id) DO UPDATE
+		SET low_stock_threshold = EXCLUDED.low_stock_threshold,
+		    auto_restock_amount = EXCLUDED.auto_restock_amount,
+		    updated_at = NOW()`
+	_, err := m.db.ExecContext(ctx, query, rule.GameID, rule.LowStockThreshold, rule.AutoRestockAmount)
+	return err
+}
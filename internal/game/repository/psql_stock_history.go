@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"fmt"
+)
+
+// stockHistoryWhere builds the WHERE clause shared by the history queries;
+// the game id is always $1.
+func stockHistoryWhere(gameID int, filter domain.StockHistoryFilter) (string, []interface{}) {
+	where := "WHERE game_id = $1"
+	args := []interface{}{gameID}
+
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where += fmt.Sprintf(" AND transaction_date >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where += fmt.Sprintf(" AND transaction_date < $%d", len(args))
+	}
+	return where, args
+}
+
+func (m *psqlGameRepository) FetchStockHistory(ctx context.Context, gameID int, filter domain.StockHistoryFilter) ([]domain.StockHistoryEntry, int, error) {
+	where, args := stockHistoryWhere(gameID, filter)
+
+	var total int
+	if err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM game_quantity_history "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, game_id, change_amount, reason, actor_user_id, transaction_date
+		FROM game_quantity_history
+		%s
+		ORDER BY transaction_date DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []domain.StockHistoryEntry{}
+	for rows.Next() {
+		var e domain.StockHistoryEntry
+		if err := rows.Scan(&e.ID, &e.GameID, &e.ChangeAmount, &e.Reason, &e.ActorUserID, &e.TransactionDate); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
+
+func (m *psqlGameRepository) FetchDailyStockSummary(ctx context.Context, gameID int, filter domain.StockHistoryFilter) ([]domain.DailyStockSummary, error) {
+	where, args := stockHistoryWhere(gameID, filter)
+
+	query := `
+		SELECT TO_CHAR(DATE(transaction_date), 'YYYY-MM-DD'),
+		       COALESCE(SUM(change_amount) FILTER (WHERE change_amount > 0), 0),
+		       COALESCE(-SUM(change_amount) FILTER (WHERE change_amount < 0), 0),
+		       SUM(change_amount),
+		       COUNT(*)
+		FROM game_quantity_history
+		` + where + `
+		GROUP BY DATE(transaction_date)
+		ORDER BY DATE(transaction_date) DESC`
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := []domain.DailyStockSummary{}
+	for rows.Next() {
+		var d domain.DailyStockSummary
+		if err := rows.Scan(&d.Date, &d.Added, &d.Removed, &d.Net, &d.Changes); err != nil {
+			return nil, err
+		}
+		summary = append(summary, d)
+	}
+	return summary, rows.Err()
+}
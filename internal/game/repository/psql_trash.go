@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (m *psqlGameRepository) FetchDeleted(ctx context.Context, publisherID int) ([]domain.Game, error) {
+	query := `SELECT ` + gameColumns + ` FROM games
+              WHERE deleted_at IS NOT NULL AND ($1 = 0 OR publisher_id = $1)
+              ORDER BY deleted_at DESC`
+	rows, err := m.db.QueryContext(ctx, query, publisherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []domain.Game{}
+	for rows.Next() {
+		g, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.loadGameRelations(ctx, &g); err != nil {
+			return nil, err
+		}
+		res = append(res, g)
+	}
+	return res, rows.Err()
+}
+
+func (m *psqlGameRepository) GetDeletedByID(ctx context.Context, id int) (domain.Game, error) {
+	query := `SELECT ` + gameColumns + ` FROM games WHERE id = $1 AND deleted_at IS NOT NULL`
+	g, err := scanGame(m.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Game{}, domain.ErrGameNotFound
+	}
+	if err != nil {
+		return domain.Game{}, err
+	}
+	return g, nil
+}
+
+func (m *psqlGameRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE games SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+	res, err := m.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrGameNotFound
+	}
+	return nil
+}
+
+// PurgeDeleted hard-deletes games soft-deleted before deletedBefore. Games that
+// were ever ordered or sit in a library are kept so sales records and owners
+// keep pointing at a real row. Base games wait until their DLC is gone, and
+// games still listed in a bundle are kept too. It returns how many games were
+// purged and the blob store keys of their media, which the caller removes
+// once the rows are gone.
+func (m *psqlGameRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, []string, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT g.id FROM games g
+		WHERE g.deleted_at IS NOT NULL AND g.deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.game_id = g.id)
+		  AND NOT EXISTS (SELECT 1 FROM customer_game_library cgl WHERE cgl.game_id = g.id)
+		  AND NOT EXISTS (SELECT 1 FROM games d WHERE d.parent_game_id = g.id)
+		  AND NOT EXISTS (SELECT 1 FROM bundle_games bg WHERE bg.game_id = g.id)
+		FOR UPDATE`, deletedBefore)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	mediaRows, err := tx.QueryContext(ctx, `
+		DELETE FROM game_media WHERE game_id = ANY($1)
+		RETURNING storage_key, thumbnail_key`, pq.Array(ids))
+	if err != nil {
+		return 0, nil, err
+	}
+	var blobKeys []string
+	for mediaRows.Next() {
+		var key, thumbKey string
+		if err := mediaRows.Scan(&key, &thumbKey); err != nil {
+			mediaRows.Close()
+			return 0, nil, err
+		}
+		blobKeys = append(blobKeys, key, thumbKey)
+	}
+	mediaRows.Close()
+	if err := mediaRows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	for _, stmt := range []string{
+		`DELETE FROM game_quantity_history WHERE game_id = ANY($1)`,
+		`DELETE FROM wishlists WHERE game_id = ANY($1)`,
+		`DELETE FROM games WHERE id = ANY($1)`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt, pq.Array(ids)); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return len(ids), blobKeys, nil
+}
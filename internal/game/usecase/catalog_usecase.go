@@ -0,0 +1,479 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"cool-games/internal/domain"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxImportRows = 5000
+	genreListSep  = ";"
+)
+
+var catalogCSVHeader = []string{"name", "developer", "developer_id", "price", "stock", "genres", "release_date"}
+
+type catalogUsecase struct {
+	catalogRepo    domain.CatalogRepository
+	contextTimeout time.Duration
+}
+
+func NewCatalogUsecase(repo domain.CatalogRepository, timeout time.Duration) domain.CatalogUsecase {
+	return &catalogUsecase{catalogRepo: repo, contextTimeout: timeout}
+}
+
+// pendingRow is a parsed import row on its way to becoming a game.
+type pendingRow struct {
+	result *domain.ImportRowResult
+	row    domain.CatalogRow
+	game   *domain.Game
+}
+
+func (u *catalogUsecase) Import(ctx context.Context, r io.Reader, opts domain.ImportOptions, requesterID int) (domain.ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "CatalogUsecase.Import")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	pubID, err := u.catalogRepo.GetPublisherIDByUserID(c, requesterID)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	rows, err := parseCatalog(r, opts.Format)
+	if err != nil {
+		return domain.ImportReport{}, domain.ErrInvalidCatalogFile.Wrap(err)
+	}
+
+	report := domain.ImportReport{Mode: opts.Mode, DryRun: opts.DryRun, Total: len(rows)}
+	if err := u.validateRows(c, rows, pubID); err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	var valid []*pendingRow
+	for i := range rows {
+		if rows[i].result.Status == domain.ImportRowValid {
+			valid = append(valid, &rows[i])
+		}
+	}
+
+	switch {
+	case opts.DryRun:
+	case opts.Mode == domain.ImportModeTransactional:
+		if len(valid) != len(rows) {
+			markSkipped(valid)
+			break
+		}
+		games := make([]*domain.Game, 0, len(valid))
+		for _, p := range valid {
+			games = append(games, p.game)
+		}
+		if err := u.catalogRepo.StoreMany(c, games); err != nil {
+			reason := storeFailure(c, err)
+			for _, p := range valid {
+				fail(p.result, "not imported: "+reason)
+			}
+			break
+		}
+		markCreated(valid)
+		report.Committed = true
+	default:
+		for _, p := range valid {
+			if err := u.catalogRepo.Store(c, p.game); err != nil {
+				fail(p.result, storeFailure(c, err))
+				continue
+			}
+			markCreated([]*pendingRow{p})
+		}
+		report.Committed = true
+	}
+
+	report.Rows = make([]domain.ImportRowResult, 0, len(rows))
+	for _, p := range rows {
+		switch p.result.Status {
+		case domain.ImportRowCreated:
+			report.Created++
+		case domain.ImportRowFailed:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, *p.result)
+	}
+	return report, nil
+}
+
+// validateRows checks every row on its own and against the database, turning
+// valid rows into games. Problems are recorded on the row, not returned.
+func (u *catalogUsecase) validateRows(ctx context.Context, rows []pendingRow, publisherID int) error {
+	var genreNames, developerNames []string
+	var developerIDs []int
+	for _, p := range rows {
+		genreNames = append(genreNames, p.row.Genres...)
+		if p.row.DeveloperID > 0 {
+			developerIDs = append(developerIDs, p.row.DeveloperID)
+		}
+		if name := strings.TrimSpace(p.row.Developer); name != "" {
+			developerNames = append(developerNames, name)
+		}
+	}
+
+	genres, err := u.catalogRepo.GetGenresByName(ctx, genreNames)
+	if err != nil {
+		return err
+	}
+	developersByID, err := u.catalogRepo.GetDeveloperNames(ctx, developerIDs)
+	if err != nil {
+		return err
+	}
+	developersByName, err := u.catalogRepo.GetDeveloperIDsByName(ctx, developerNames)
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		p := &rows[i]
+		if len(p.result.Errors) > 0 {
+			p.result.Status = domain.ImportRowFailed
+			continue
+		}
+
+		developerID, problem := resolveDeveloper(p.row, developersByID, developersByName)
+		if problem != "" {
+			p.result.Errors = append(p.result.Errors, problem)
+		}
+
+		g := &domain.Game{
+			PublisherID: publisherID,
+			DeveloperID: developerID,
+			Name:        strings.TrimSpace(p.row.Name),
+			Price:       p.row.Price,
+			StockLevel:  p.row.Stock,
+		}
+
+		if err := validateGame(*g); err != nil {
+			var verr *domain.ValidationError
+			if errors.As(err, &verr) {
+				for field, reason := range verr.Fields {
+					// A developer that did not resolve is already reported.
+					if field == "developer_id" && problem != "" {
+						continue
+					}
+					if field == "developer_id" {
+						reason = "or developer is required"
+					}
+					p.result.Errors = append(p.result.Errors, field+" "+reason)
+				}
+			}
+		}
+		if g.StockLevel < 0 {
+			p.result.Errors = append(p.result.Errors, "stock must not be negative")
+		}
+
+		seen := map[int]bool{}
+		for _, name := range p.row.Genres {
+			gen, ok := genres[strings.ToLower(name)]
+			if !ok {
+				p.result.Errors = append(p.result.Errors, fmt.Sprintf("unknown genre %q", name))
+				continue
+			}
+			if !seen[gen.ID] {
+				seen[gen.ID] = true
+				g.Genres = append(g.Genres, gen)
+			}
+		}
+
+		if p.row.ReleaseDate != "" {
+			date, err := time.Parse("2006-01-02", p.row.ReleaseDate)
+			if err != nil {
+				p.result.Errors = append(p.result.Errors, "release_date must be YYYY-MM-DD")
+			}
+			g.ReleaseDate = date
+		}
+
+		if len(p.result.Errors) > 0 {
+			p.result.Status = domain.ImportRowFailed
+			continue
+		}
+		p.game = g
+	}
+	return nil
+}
+
+// resolveDeveloper finds the row's developer by id or by name. When both are
+// given they must name the same developer. The second result explains a
+// developer that does not resolve.
+func resolveDeveloper(row domain.CatalogRow, byID map[int]string, byName map[string][]int) (int, string) {
+	name := strings.TrimSpace(row.Developer)
+	if row.DeveloperID > 0 {
+		actual, ok := byID[row.DeveloperID]
+		if !ok {
+			return 0, fmt.Sprintf("developer %d does not exist", row.DeveloperID)
+		}
+		if name != "" && !strings.EqualFold(name, actual) {
+			return 0, fmt.Sprintf("developer %d is %q, not %q", row.DeveloperID, actual, name)
+		}
+		return row.DeveloperID, ""
+	}
+	if name == "" {
+		return 0, ""
+	}
+
+	ids := byName[strings.ToLower(name)]
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Sprintf("unknown developer %q", name)
+	case 1:
+		return ids[0], ""
+	default:
+		return 0, fmt.Sprintf("%d developers are called %q, set developer_id", len(ids), name)
+	}
+}
+
+// storeFailure is the reason reported for a row the database refused.
+// Internal errors are logged instead of quoted, so the report never carries
+// database messages.
+func storeFailure(ctx context.Context, err error) string {
+	if domain.KindOf(err) != domain.KindInternal {
+		return err.Error()
+	}
+	slog.ErrorContext(ctx, "catalog: storing imported games failed", "err", err)
+	return "could not be stored"
+}
+
+func (u *catalogUsecase) Export(ctx context.Context, w io.Writer, format string, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "CatalogUsecase.Export")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	var games []domain.Game
+	var err error
+	if role == "admin" {
+		games, err = u.catalogRepo.Fetch(c, domain.GameFilter{})
+	} else {
+		pubID, perr := u.catalogRepo.GetPublisherIDByUserID(c, requesterID)
+		if perr != nil {
+			return perr
+		}
+		games, err = u.catalogRepo.FetchByPublisher(c, pubID)
+	}
+	if err != nil {
+		return err
+	}
+
+	developerIDs := make([]int, 0, len(games))
+	for _, g := range games {
+		developerIDs = append(developerIDs, g.DeveloperID)
+	}
+	developers, err := u.catalogRepo.GetDeveloperNames(c, developerIDs)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]domain.CatalogRow, 0, len(games))
+	for _, g := range games {
+		row := domain.CatalogRow{
+			Name:        g.Name,
+			Developer:   developers[g.DeveloperID],
+			DeveloperID: g.DeveloperID,
+			Price:       g.Price,
+			Stock:       g.StockLevel,
+			Genres:      []string{},
+		}
+		for _, gen := range g.Genres {
+			row.Genres = append(row.Genres, gen.Name)
+		}
+		if !g.ReleaseDate.IsZero() {
+			row.ReleaseDate = g.ReleaseDate.Format("2006-01-02")
+		}
+		rows = append(rows, row)
+	}
+
+	if format == domain.CatalogFormatJSONL {
+		enc := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(catalogCSVHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Name,
+			row.Developer,
+			strconv.Itoa(row.DeveloperID),
+			strconv.FormatFloat(row.Price, 'f', 2, 64),
+			strconv.Itoa(row.Stock),
+			strings.Join(row.Genres, genreListSep),
+			row.ReleaseDate,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// parseCatalog reads every row of the file. Rows that cannot be decoded are
+// kept with their errors so the report still lines up with the input.
+func parseCatalog(r io.Reader, format string) ([]pendingRow, error) {
+	var rows []pendingRow
+	add := func(n int, row domain.CatalogRow, errs []string) error {
+		if len(rows) >= maxImportRows {
+			return fmt.Errorf("import is limited to %d rows", maxImportRows)
+		}
+		rows = append(rows, pendingRow{
+			row:    row,
+			result: &domain.ImportRowResult{Row: n, Name: row.Name, Status: domain.ImportRowValid, Errors: errs},
+		})
+		return nil
+	}
+
+	if format == domain.CatalogFormatJSONL {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1<<20)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			var row domain.CatalogRow
+			var errs []string
+			if err := json.Unmarshal([]byte(text), &row); err != nil {
+				errs = append(errs, "invalid JSON: "+err.Error())
+			}
+			if err := add(line, row, errs); err != nil {
+				return nil, err
+			}
+		}
+		return rows, scanner.Err()
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+	_, hasName := columns["developer"]
+	_, hasID := columns["developer_id"]
+	if !hasName && !hasID {
+		return nil, errors.New(`CSV header needs a "developer" or "developer_id" column`)
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		// A malformed line, such as a stray quote, fails only its own row;
+		// the reader carries on with the next line.
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			if err := add(perr.StartLine, domain.CatalogRow{}, []string{"invalid CSV: " + perr.Err.Error()}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		row, errs := csvRecordToRow(record, columns)
+		if len(record) != len(header) {
+			errs = append(errs, fmt.Sprintf("has %d fields, the header has %d", len(record), len(header)))
+		}
+		if err := add(line, row, errs); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+func csvRecordToRow(record []string, columns map[string]int) (domain.CatalogRow, []string) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var row domain.CatalogRow
+	var errs []string
+	row.Name = get("name")
+	row.Developer = get("developer")
+	row.ReleaseDate = get("release_date")
+
+	if v := get("developer_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, "developer_id must be a number")
+		}
+		row.DeveloperID = id
+	}
+	if v := get("price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, "price must be a number")
+		}
+		row.Price = price
+	}
+	if v := get("stock"); v != "" {
+		stock, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, "stock must be a whole number")
+		}
+		row.Stock = stock
+	}
+	for _, name := range strings.Split(get("genres"), genreListSep) {
+		if name = strings.TrimSpace(name); name != "" {
+			row.Genres = append(row.Genres, name)
+		}
+	}
+	return row, errs
+}
+
+func fail(result *domain.ImportRowResult, reason string) {
+	result.Status = domain.ImportRowFailed
+	result.Errors = append(result.Errors, reason)
+}
+
+func markCreated(rows []*pendingRow) {
+	for _, p := range rows {
+		p.result.Status = domain.ImportRowCreated
+		p.result.GameID = p.game.ID
+	}
+}
+
+func markSkipped(rows []*pendingRow) {
+	for _, p := range rows {
+		p.result.Status = domain.ImportRowSkipped
+	}
+}
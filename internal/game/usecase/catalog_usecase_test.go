@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"cool-games/internal/domain"
+	"strings"
+	"testing"
+)
+
+func TestResolveDeveloper(t *testing.T) {
+	byID := map[int]string{1: "Moonlit", 2: "Northwind", 3: "Northwind"}
+	byName := map[string][]int{"moonlit": {1}, "northwind": {2, 3}}
+
+	tests := []struct {
+		name    string
+		row     domain.CatalogRow
+		want    int
+		problem string
+	}{
+		{"by id", domain.CatalogRow{DeveloperID: 1}, 1, ""},
+		{"by name, any case", domain.CatalogRow{Developer: " MOONLIT "}, 1, ""},
+		{"id and matching name", domain.CatalogRow{Developer: "northwind", DeveloperID: 3}, 3, ""},
+		{"id disambiguates a shared name", domain.CatalogRow{Developer: "Northwind", DeveloperID: 2}, 2, ""},
+		{"neither", domain.CatalogRow{}, 0, ""},
+		{"unknown id", domain.CatalogRow{DeveloperID: 9}, 0, "developer 9 does not exist"},
+		{"unknown name", domain.CatalogRow{Developer: "Nobody"}, 0, `unknown developer "Nobody"`},
+		{"shared name", domain.CatalogRow{Developer: "Northwind"}, 0, `2 developers are called "Northwind", set developer_id`},
+		{"id and other name", domain.CatalogRow{Developer: "Moonlit", DeveloperID: 2}, 0, `developer 2 is "Northwind", not "Moonlit"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, problem := resolveDeveloper(tt.row, byID, byName)
+			if got != tt.want || problem != tt.problem {
+				t.Errorf("got %d %q, want %d %q", got, problem, tt.want, tt.problem)
+			}
+		})
+	}
+}
+
+func TestParseCatalogCSVDeveloperColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    domain.CatalogRow
+		wantErr bool
+	}{
+		{
+			name: "developer by name",
+			csv:  "name,developer,price\nHollow Pines,Moonlit,19.99\n",
+			want: domain.CatalogRow{Name: "Hollow Pines", Developer: "Moonlit", Price: 19.99},
+		},
+		{
+			name: "developer by id",
+			csv:  "name,developer_id,price\nHollow Pines,4,19.99\n",
+			want: domain.CatalogRow{Name: "Hollow Pines", DeveloperID: 4, Price: 19.99},
+		},
+		{
+			name:    "no developer column",
+			csv:     "name,price\nHollow Pines,19.99\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseCatalog(strings.NewReader(tt.csv), domain.CatalogFormatCSV)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("file accepted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("got %d rows, want 1", len(rows))
+			}
+			got := rows[0].row
+			if got.Name != tt.want.Name || got.Developer != tt.want.Developer || got.DeveloperID != tt.want.DeveloperID || got.Price != tt.want.Price {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCatalogCSVBadLines(t *testing.T) {
+	csv := "name,developer,price\n" +
+		"Hollow Pines,Moonlit,19.99\n" +
+		"Bad \"Quote,Moonlit,5\n" +
+		"Too Short,Moonlit\n" +
+		"Too Long,Moonlit,5,extra\n" +
+		"Dune Runner,Moonlit,9.99\n"
+
+	rows, err := parseCatalog(strings.NewReader(csv), domain.CatalogFormatCSV)
+	if err != nil {
+		t.Fatalf("file rejected: %v", err)
+	}
+
+	want := []struct {
+		line   int
+		name   string
+		failed bool
+	}{
+		{line: 2, name: "Hollow Pines"},
+		{line: 3, failed: true},
+		{line: 4, name: "Too Short", failed: true},
+		{line: 5, name: "Too Long", failed: true},
+		{line: 6, name: "Dune Runner"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		got := rows[i].result
+		if got.Row != w.line || rows[i].row.Name != w.name || (len(got.Errors) > 0) != w.failed {
+			t.Errorf("row %d: line %d, name %q, errors %v; want line %d, name %q, failed %t",
+				i, got.Row, rows[i].row.Name, got.Errors, w.line, w.name, w.failed)
+		}
+	}
+}
@@ -3,48 +3,135 @@ package usecase
 import (
 	"context"
 	"cool-games/internal/domain"
-	"errors"
+	"log/slog"
 	"time"
+
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("cool-games/internal/game/usecase")
+
 type gameUsecase struct {
 	gameRepo       domain.GameRepository
+	prices         domain.PriceResolver
+	blobs          domain.BlobStore
 	contextTimeout time.Duration
 }
 
-func NewGameUsecase(g domain.GameRepository, timeout time.Duration) domain.GameUsecase {
-	return &gameUsecase{gameRepo: g, contextTimeout: timeout}
+func NewGameUsecase(g domain.GameRepository, p domain.PriceResolver, blobs domain.BlobStore, timeout time.Duration) domain.GameUsecase {
+	return &gameUsecase{gameRepo: g, prices: p, blobs: blobs, contextTimeout: timeout}
 }
 
-func (u *gameUsecase) GetAll(ctx context.Context, search string, minPrice, maxPrice float64) ([]domain.Game, error) {
+func (u *gameUsecase) GetAll(ctx context.Context, filter domain.GameFilter) ([]domain.Game, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetAll")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
-	return u.gameRepo.Fetch(c, search, minPrice, maxPrice)
+	return u.gameRepo.Fetch(c, filter)
 }
 
-func (u *gameUsecase) GetByID(ctx context.Context, id int) (domain.Game, error) {
+// GetByID also lists the game's DLC, flagging the ones viewerUserID owns
+// (0 for anonymous callers), and what the viewer would pay in their region
+// and currency.
+func (u *gameUsecase) GetByID(ctx context.Context, id int, viewerUserID int) (domain.Game, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetByID")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
-	return u.gameRepo.GetByID(c, id)
+
+	g, err := u.gameRepo.GetByID(c, id)
+	if err != nil {
+		return domain.Game{}, err
+	}
+	if g.ParentGameID == nil {
+		if g.DLC, err = u.gameRepo.FetchDLC(c, id, viewerUserID); err != nil {
+			return domain.Game{}, err
+		}
+	}
+	if viewerUserID > 0 && u.prices != nil {
+		price, err := u.prices.PriceFor(c, id, g.Price, viewerUserID)
+		if err != nil {
+			return domain.Game{}, err
+		}
+		g.LocalPrice = &price
+	}
+	return g, nil
+}
+
+// checkParentGame makes sure a DLC hangs off a live base game of the same
+// publisher. Only one level is allowed: DLC cannot have DLC of its own.
+func (u *gameUsecase) checkParentGame(ctx context.Context, g domain.Game) error {
+	if g.ParentGameID == nil {
+		return nil
+	}
+
+	verr := domain.NewValidationError()
+	parent, err := u.gameRepo.GetByID(ctx, *g.ParentGameID)
+	switch {
+	case err == domain.ErrGameNotFound:
+		verr.Add("parent_game_id", "base game not found")
+	case err != nil:
+		return err
+	case parent.ID == g.ID:
+		verr.Add("parent_game_id", "a game cannot be its own base game")
+	case parent.PublisherID != g.PublisherID:
+		verr.Add("parent_game_id", "base game belongs to another publisher")
+	case parent.ParentGameID != nil:
+		verr.Add("parent_game_id", "base game is itself a DLC")
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+
+	if g.ID > 0 {
+		dlc, err := u.gameRepo.FetchDLC(ctx, g.ID, 0)
+		if err != nil {
+			return err
+		}
+		if len(dlc) > 0 {
+			verr.Add("parent_game_id", "a game with DLC cannot become a DLC")
+			return verr
+		}
+	}
+	return nil
 }
 
 func (u *gameUsecase) Create(ctx context.Context, g *domain.Game, requesterID int) error {
+	ctx, span := tracer.Start(ctx, "GameUsecase.Create")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
+	if err := validateGameDetails(*g); err != nil {
+		return err
+	}
+
 	pubID, err := u.gameRepo.GetPublisherIDByUserID(c, requesterID)
 	if err != nil {
-		return errors.New("publisher profile not found")
+		return err
 	}
 
 	g.PublisherID = pubID
+	if err := u.checkParentGame(c, *g); err != nil {
+		return err
+	}
 	return u.gameRepo.Store(c, g)
 }
 
-func (u *gameUsecase) Update(ctx context.Context, id int, g *domain.Game, requesterID int, role string) error {
+// Update applies an edit on top of expectedVersion, or on top of the version
+// it just read when expectedVersion is 0. Either way a concurrent edit that
+// lands first makes it fail with ErrVersionConflict instead of being lost.
+// Like Patch, an edit that changes nothing is not saved.
+func (u *gameUsecase) Update(ctx context.Context, id int, g *domain.Game, requesterID int, role string, expectedVersion int) error {
+	ctx, span := tracer.Start(ctx, "GameUsecase.Update")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
+	if err := validateGameDetails(*g); err != nil {
+		return err
+	}
+
 	existing, err := u.gameRepo.GetByID(c, id)
 	if err != nil {
 		return err
@@ -57,12 +144,112 @@ func (u *gameUsecase) Update(ctx context.Context, id int, g *domain.Game, reques
 		}
 	}
 
+	if expectedVersion > 0 && expectedVersion != existing.Version {
+		return domain.ErrVersionConflict
+	}
+
 	g.ID = id
 	g.PublisherID = existing.PublisherID
-	return u.gameRepo.Update(c, g)
+	g.Version = existing.Version
+	changes := diffGames(existing, *g)
+	if len(changes) == 0 {
+		*g = existing
+		return nil
+	}
+	if err := u.checkParentGame(c, *g); err != nil {
+		return err
+	}
+	audit := newAuditEntry(id, requesterID, domain.GameAuditUpdate, changes)
+	if err := u.gameRepo.Update(c, g, audit); err != nil {
+		return err
+	}
+
+	updated, err := u.gameRepo.GetByID(c, id)
+	if err != nil {
+		return err
+	}
+	*g = updated
+	return nil
+}
+
+// Patch merges an RFC 7396 patch into the game and saves only if something
+// actually changed, recording the changed fields in the audit trail.
+func (u *gameUsecase) Patch(ctx context.Context, id int, patch []byte, requesterID int, role string, expectedVersion int) (domain.Game, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.Patch")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.gameRepo.GetByID(c, id)
+	if err != nil {
+		return domain.Game{}, err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return domain.Game{}, err
+	}
+	if expectedVersion > 0 && expectedVersion != existing.Version {
+		return domain.Game{}, domain.ErrVersionConflict
+	}
+
+	patched, err := applyGamePatch(existing, patch)
+	if err != nil {
+		return domain.Game{}, err
+	}
+
+	changes := diffGames(existing, patched)
+	if len(changes) == 0 {
+		return existing, nil
+	}
+	if _, ok := changes["parent_game_id"]; ok {
+		if err := u.checkParentGame(c, patched); err != nil {
+			return domain.Game{}, err
+		}
+	}
+
+	audit := newAuditEntry(id, requesterID, domain.GameAuditPatch, changes)
+	if err := u.gameRepo.Update(c, &patched, audit); err != nil {
+		return domain.Game{}, err
+	}
+	return u.gameRepo.GetByID(c, id)
+}
+
+func (u *gameUsecase) GetAuditTrail(ctx context.Context, id int, requesterID int, role string) ([]domain.GameAuditEntry, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetAuditTrail")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.gameRepo.GetByID(c, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return nil, err
+	}
+
+	return u.gameRepo.FetchAuditTrail(c, id)
+}
+
+func (u *gameUsecase) GetPriceHistory(ctx context.Context, id int, requesterID int, role string) ([]domain.PriceHistoryEntry, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetPriceHistory")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.gameRepo.GetByID(c, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return nil, err
+	}
+
+	return u.gameRepo.FetchPriceHistory(c, id)
 }
 
 func (u *gameUsecase) Delete(ctx context.Context, id int, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "GameUsecase.Delete")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
@@ -82,6 +269,8 @@ func (u *gameUsecase) Delete(ctx context.Context, id int, requesterID int, role
 }
 
 func (u *gameUsecase) GetByPublisher(ctx context.Context, requesterID int) ([]domain.Game, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetByPublisher")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
@@ -92,7 +281,9 @@ func (u *gameUsecase) GetByPublisher(ctx context.Context, requesterID int) ([]do
 	return u.gameRepo.FetchByPublisher(c, pubID)
 }
 
-func (u *gameUsecase) Restock(ctx context.Context, gameID int, requesterID int, amount int) error {
+func (u *gameUsecase) Restock(ctx context.Context, gameID int, requesterID int, req domain.RestockRequest) error {
+	ctx, span := tracer.Start(ctx, "GameUsecase.Restock")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
@@ -106,5 +297,191 @@ func (u *gameUsecase) Restock(ctx context.Context, gameID int, requesterID int,
 		return domain.ErrUnauthorizedAction
 	}
 
-	return u.gameRepo.UpdateStock(c, gameID, amount)
-}
\ No newline at end of file
+	reason := req.Reason
+	if reason == "" {
+		reason = domain.StockReasonManualRestock
+	}
+	if reason == domain.StockReasonManualRestock && req.Amount < 0 {
+		return domain.ErrRestockNotPositive
+	}
+
+	return u.gameRepo.UpdateStock(c, domain.StockChange{
+		GameID:      gameID,
+		Change:      req.Amount,
+		Reason:      reason,
+		ActorUserID: &requesterID,
+	})
+}
+
+// authorizeOwner lets admins through and otherwise requires the requester to
+// be the publisher that owns the game.
+func (u *gameUsecase) authorizeOwner(ctx context.Context, game domain.Game, requesterID int, role string) error {
+	if role == "admin" {
+		return nil
+	}
+	pubID, err := u.gameRepo.GetPublisherIDByUserID(ctx, requesterID)
+	if err != nil || game.PublisherID != pubID {
+		return domain.ErrUnauthorizedAction
+	}
+	return nil
+}
+
+func (u *gameUsecase) GetStockRule(ctx context.Context, gameID int, requesterID int, role string) (domain.StockRule, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetStockRule")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.gameRepo.GetByID(c, gameID)
+	if err != nil {
+		return domain.StockRule{}, err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return domain.StockRule{}, err
+	}
+
+	return u.gameRepo.GetStockRule(c, gameID)
+}
+
+func (u *gameUsecase) SetStockRule(ctx context.Context, rule *domain.StockRule, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "GameUsecase.SetStockRule")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.gameRepo.GetByID(c, rule.GameID)
+	if err != nil {
+		return err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return err
+	}
+
+	if rule.AutoRestockAmount > 0 && rule.LowStockThreshold == 0 {
+		return domain.ErrAutoRestockNoLimit
+	}
+
+	return u.gameRepo.SaveStockRule(c, rule)
+}
+
+func (u *gameUsecase) GetStockHistory(ctx context.Context, gameID int, requesterID int, role string, filter domain.StockHistoryFilter) (domain.StockHistoryPage, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetStockHistory")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.gameRepo.GetByID(c, gameID)
+	if err != nil {
+		return domain.StockHistoryPage{}, err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return domain.StockHistoryPage{}, err
+	}
+
+	items, total, err := u.gameRepo.FetchStockHistory(c, gameID, filter)
+	if err != nil {
+		return domain.StockHistoryPage{}, err
+	}
+
+	return domain.StockHistoryPage{
+		Items:    items,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Total:    total,
+	}, nil
+}
+
+func (u *gameUsecase) GetDailyStockSummary(ctx context.Context, gameID int, requesterID int, role string, filter domain.StockHistoryFilter) ([]domain.DailyStockSummary, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetDailyStockSummary")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	existing, err := u.gameRepo.GetByID(c, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.authorizeOwner(c, existing, requesterID, role); err != nil {
+		return nil, err
+	}
+
+	return u.gameRepo.FetchDailyStockSummary(c, gameID, filter)
+}
+
+func (u *gameUsecase) GetTrash(ctx context.Context, requesterID int, role string) ([]domain.Game, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.GetTrash")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if role == "admin" {
+		return u.gameRepo.FetchDeleted(c, 0)
+	}
+
+	pubID, err := u.gameRepo.GetPublisherIDByUserID(c, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	return u.gameRepo.FetchDeleted(c, pubID)
+}
+
+func (u *gameUsecase) Restore(ctx context.Context, id int, requesterID int, role string) (domain.Game, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.Restore")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	deleted, err := u.gameRepo.GetDeletedByID(c, id)
+	if err != nil {
+		return domain.Game{}, err
+	}
+	if err := u.authorizeOwner(c, deleted, requesterID, role); err != nil {
+		return domain.Game{}, err
+	}
+
+	if err := u.gameRepo.Restore(c, id); err != nil {
+		return domain.Game{}, err
+	}
+	return u.gameRepo.GetByID(c, id)
+}
+
+func (u *gameUsecase) PurgeTrash(ctx context.Context, retention time.Duration) (int, error) {
+	ctx, span := tracer.Start(ctx, "GameUsecase.PurgeTrash")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	purged, blobKeys, err := u.gameRepo.PurgeDeleted(c, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	// The rows are gone, so a file that cannot be removed is only logged.
+	for _, key := range blobKeys {
+		if err := u.blobs.Delete(c, key); err != nil {
+			slog.WarnContext(c, "game: failed to delete media blob of purged game", "key", key, "err", err)
+		}
+	}
+	return purged, nil
+}
+
+// RunTrashPurger calls PurgeTrash every interval until ctx is cancelled.
+func RunTrashPurger(ctx context.Context, us domain.GameUsecase, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := us.PurgeTrash(ctx, retention)
+			if err != nil {
+				slog.ErrorContext(ctx, "game: trash purge failed", "err", err)
+				continue
+			}
+			if purged > 0 {
+				slog.InfoContext(ctx, "game: purged deleted games", "count", purged)
+			}
+		}
+	}
+}
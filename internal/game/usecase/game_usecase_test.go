@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"testing"
+	"time"
+)
+
+// editRepo holds one game owned by publisher 1 and counts saved edits.
+type editRepo struct {
+	domain.GameRepository
+	game    domain.Game
+	updates []*domain.GameAuditEntry
+}
+
+func (r *editRepo) GetByID(ctx context.Context, id int) (domain.Game, error) {
+	return r.game, nil
+}
+
+func (r *editRepo) GetPublisherIDByUserID(ctx context.Context, userID int) (int, error) {
+	return 1, nil
+}
+
+func (r *editRepo) Update(ctx context.Context, g *domain.Game, audit *domain.GameAuditEntry) error {
+	r.updates = append(r.updates, audit)
+	// Edits never touch stock.
+	stock := r.game.StockLevel
+	r.game = *g
+	r.game.Version++
+	r.game.StockLevel = stock
+	return nil
+}
+
+func TestUpdateSkipsEditsThatChangeNothing(t *testing.T) {
+	stored := domain.Game{
+		ID: 5, PublisherID: 1, DeveloperID: 2, Name: "Hollow Pines", Price: 19.99,
+		StockLevel: 8, Genres: []domain.Genre{{ID: 3, Name: "Puzzle"}}, Version: 4,
+	}
+
+	tests := []struct {
+		name        string
+		edit        func(g *domain.Game)
+		wantUpdate  bool
+		wantVersion int
+	}{
+		{
+			name:        "same values",
+			edit:        func(g *domain.Game) {},
+			wantVersion: 4,
+		},
+		{
+			name:        "same genres without names",
+			edit:        func(g *domain.Game) { g.Genres = []domain.Genre{{ID: 3}} },
+			wantVersion: 4,
+		},
+		{
+			name:        "new price",
+			edit:        func(g *domain.Game) { g.Price = 14.99 },
+			wantUpdate:  true,
+			wantVersion: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &editRepo{game: stored}
+			u := NewGameUsecase(repo, nil, nil, time.Minute)
+
+			// A PUT body carries the editable fields only.
+			g := domain.Game{DeveloperID: stored.DeveloperID, Name: stored.Name, Price: stored.Price, Genres: stored.Genres}
+			tt.edit(&g)
+			if err := u.Update(context.Background(), stored.ID, &g, 10, "publisher", stored.Version); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+
+			if got := len(repo.updates) > 0; got != tt.wantUpdate {
+				t.Errorf("saved: %t, want %t (audit %+v)", got, tt.wantUpdate, repo.updates)
+			}
+			if g.Version != tt.wantVersion || g.StockLevel != stored.StockLevel {
+				t.Errorf("returned version %d, stock %d; want version %d, stock %d", g.Version, g.StockLevel, tt.wantVersion, stored.StockLevel)
+			}
+		})
+	}
+}
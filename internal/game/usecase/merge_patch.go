@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"cool-games/internal/domain"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// patchableFields are the game fields a merge patch may touch. Everything
+// else (id, publisher, stock, version, ...) is owned by the server.
+var patchableFields = map[string]bool{
+	"developer_id":             true,
+	"game_name":                true,
+	"price":                    true,
+	"genres":                   true,
+	"short_description":        true,
+	"description":              true,
+	"min_requirements":         true,
+	"recommended_requirements": true,
+	"age_rating_system":        true,
+	"age_rating":               true,
+	"languages":                true,
+	"parent_game_id":           true,
+}
+
+// mergePatch applies an RFC 7396 JSON Merge Patch to target: objects merge
+// key by key, null removes a key, and any other value replaces it outright.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+// applyGamePatch returns existing with patch merged into it, rejecting patches
+// that touch server-owned fields or leave the game invalid.
+func applyGamePatch(existing domain.Game, patch []byte) (domain.Game, error) {
+	var patchDoc map[string]interface{}
+	if err := json.Unmarshal(patch, &patchDoc); err != nil || patchDoc == nil {
+		return domain.Game{}, domain.ErrInvalidMergePatch
+	}
+
+	verr := domain.NewValidationError()
+	for field := range patchDoc {
+		if !patchableFields[field] {
+			verr.Add(field, "field cannot be changed")
+		}
+	}
+	if verr.HasErrors() {
+		return domain.Game{}, verr
+	}
+
+	current, err := json.Marshal(existing)
+	if err != nil {
+		return domain.Game{}, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(current, &doc); err != nil {
+		return domain.Game{}, err
+	}
+
+	merged, err := json.Marshal(mergePatch(doc, patchDoc))
+	if err != nil {
+		return domain.Game{}, err
+	}
+
+	var result domain.Game
+	if err := json.Unmarshal(merged, &result); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			verr.Add(typeErr.Field, "expected "+typeErr.Type.String())
+			return domain.Game{}, verr
+		}
+		return domain.Game{}, err
+	}
+
+	if err := validateGame(result); err != nil {
+		return domain.Game{}, err
+	}
+	return result, nil
+}
+
+func validateGame(g domain.Game) error {
+	verr := domain.NewValidationError()
+	if g.Name == "" {
+		verr.Add("game_name", "is required")
+	} else if len(g.Name) > 255 {
+		verr.Add("game_name", "must be at most 255 characters")
+	}
+	if g.DeveloperID <= 0 {
+		verr.Add("developer_id", "is required")
+	}
+	if g.Price <= 0 {
+		verr.Add("price", "must be greater than 0")
+	}
+
+	seen := map[int]bool{}
+	for _, gen := range g.Genres {
+		if gen.ID <= 0 {
+			verr.Add("genres", "every genre needs an id")
+			break
+		}
+		if seen[gen.ID] {
+			verr.Add("genres", "genres must not repeat")
+			break
+		}
+		seen[gen.ID] = true
+	}
+
+	addDetailErrors(verr, g)
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+const (
+	maxShortDescriptionLen = 300
+	maxDescriptionLen      = 20000
+)
+
+// languageCode accepts ISO 639 codes with an optional region, e.g. "en",
+// "fil" or "pt-BR".
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// validateGameDetails checks the descriptive fields on create and full
+// updates, where the remaining fields are enforced by request binding.
+func validateGameDetails(g domain.Game) error {
+	verr := domain.NewValidationError()
+	addDetailErrors(verr, g)
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+func addDetailErrors(verr *domain.ValidationError, g domain.Game) {
+	if len([]rune(g.ShortDescription)) > maxShortDescriptionLen {
+		verr.Add("short_description", "must be at most 300 characters")
+	}
+	if len([]rune(g.Description)) > maxDescriptionLen {
+		verr.Add("description", "must be at most 20000 characters")
+	}
+
+	switch {
+	case g.AgeRatingSystem == "" && g.AgeRating == "":
+	case g.AgeRatingSystem == "" || g.AgeRating == "":
+		verr.Add("age_rating", "age_rating_system and age_rating must be set together")
+	case g.AgeRatingSystem != domain.AgeRatingPEGI && g.AgeRatingSystem != domain.AgeRatingESRB:
+		verr.Add("age_rating_system", "must be PEGI or ESRB")
+	case !domain.ValidAgeRating(g.AgeRatingSystem, g.AgeRating):
+		verr.Add("age_rating", "is not a valid "+g.AgeRatingSystem+" rating")
+	}
+
+	seen := map[string]bool{}
+	for _, lang := range g.Languages {
+		if !languageCode.MatchString(lang) {
+			verr.Add("languages", "invalid language code "+lang)
+			break
+		}
+		if seen[lang] {
+			verr.Add("languages", "languages must not repeat")
+			break
+		}
+		seen[lang] = true
+	}
+}
+
+// diffGames lists the editable fields that differ between before and after.
+func diffGames(before, after domain.Game) map[string]domain.FieldChange {
+	changes := map[string]domain.FieldChange{}
+	if before.DeveloperID != after.DeveloperID {
+		changes["developer_id"] = domain.FieldChange{From: before.DeveloperID, To: after.DeveloperID}
+	}
+	if before.Name != after.Name {
+		changes["game_name"] = domain.FieldChange{From: before.Name, To: after.Name}
+	}
+	if before.Price != after.Price {
+		changes["price"] = domain.FieldChange{From: before.Price, To: after.Price}
+	}
+	if from, to := genreIDs(before.Genres), genreIDs(after.Genres); !reflect.DeepEqual(from, to) {
+		changes["genres"] = domain.FieldChange{From: from, To: to}
+	}
+	if before.ShortDescription != after.ShortDescription {
+		changes["short_description"] = domain.FieldChange{From: before.ShortDescription, To: after.ShortDescription}
+	}
+	if before.Description != after.Description {
+		changes["description"] = domain.FieldChange{From: before.Description, To: after.Description}
+	}
+	if !reflect.DeepEqual(before.MinRequirements, after.MinRequirements) {
+		changes["min_requirements"] = domain.FieldChange{From: before.MinRequirements, To: after.MinRequirements}
+	}
+	if !reflect.DeepEqual(before.RecommendedRequirements, after.RecommendedRequirements) {
+		changes["recommended_requirements"] = domain.FieldChange{From: before.RecommendedRequirements, To: after.RecommendedRequirements}
+	}
+	if before.AgeRatingSystem != after.AgeRatingSystem {
+		changes["age_rating_system"] = domain.FieldChange{From: before.AgeRatingSystem, To: after.AgeRatingSystem}
+	}
+	if before.AgeRating != after.AgeRating {
+		changes["age_rating"] = domain.FieldChange{From: before.AgeRating, To: after.AgeRating}
+	}
+	if from, to := sortedLanguages(before.Languages), sortedLanguages(after.Languages); !reflect.DeepEqual(from, to) {
+		changes["languages"] = domain.FieldChange{From: from, To: to}
+	}
+	if !reflect.DeepEqual(before.ParentGameID, after.ParentGameID) {
+		changes["parent_game_id"] = domain.FieldChange{From: before.ParentGameID, To: after.ParentGameID}
+	}
+	return changes
+}
+
+func sortedLanguages(languages []string) []string {
+	sorted := append([]string{}, languages...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func genreIDs(genres []domain.Genre) []int {
+	ids := make([]int, 0, len(genres))
+	for _, g := range genres {
+		ids = append(ids, g.ID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func newAuditEntry(gameID, actorUserID int, action string, changes map[string]domain.FieldChange) *domain.GameAuditEntry {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return &domain.GameAuditEntry{
+		GameID:        gameID,
+		ActorUserID:   actorUserID,
+		Action:        action,
+		ChangedFields: fields,
+		Changes:       changes,
+	}
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"cool-games/internal/domain"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// The examples of RFC 7396, appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" + "+tt.patch, func(t *testing.T) {
+			var target, patch, want interface{}
+			mustUnmarshal(t, tt.target, &target)
+			mustUnmarshal(t, tt.patch, &patch)
+			mustUnmarshal(t, tt.want, &want)
+
+			if got := mergePatch(target, patch); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyGamePatch(t *testing.T) {
+	existing := domain.Game{
+		ID:          1,
+		PublisherID: 2,
+		DeveloperID: 3,
+		Name:        "Hollow Pines",
+		Price:       19.99,
+		StockLevel:  5,
+		Languages:   []string{"en"},
+		Genres:      []domain.Genre{{ID: 4}},
+		Version:     7,
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		check   func(t *testing.T, g domain.Game)
+		wantErr error
+		fields  []string // fields named in the validation error
+	}{
+		{
+			name:  "replaces the given fields only",
+			patch: `{"price": 24.99, "languages": ["en", "pt-BR"]}`,
+			check: func(t *testing.T, g domain.Game) {
+				if g.Price != 24.99 || !reflect.DeepEqual(g.Languages, []string{"en", "pt-BR"}) {
+					t.Errorf("patched fields not applied: %+v", g)
+				}
+				if g.Name != existing.Name || g.StockLevel != existing.StockLevel || g.Version != existing.Version {
+					t.Errorf("untouched fields changed: %+v", g)
+				}
+			},
+		},
+		{
+			name:  "null clears an optional field",
+			patch: `{"genres": null}`,
+			check: func(t *testing.T, g domain.Game) {
+				if len(g.Genres) != 0 {
+					t.Errorf("genres = %v, want none", g.Genres)
+				}
+			},
+		},
+		{
+			name:    "not an object",
+			patch:   `[1, 2]`,
+			wantErr: domain.ErrInvalidMergePatch,
+		},
+		{
+			name:    "not JSON",
+			patch:   `{"price":`,
+			wantErr: domain.ErrInvalidMergePatch,
+		},
+		{
+			name:   "server-owned fields",
+			patch:  `{"stock_level": 100, "version": 1}`,
+			fields: []string{"stock_level", "version"},
+		},
+		{
+			name:   "wrong type",
+			patch:  `{"price": "free"}`,
+			fields: []string{"price"},
+		},
+		{
+			name:   "null on a required field",
+			patch:  `{"game_name": null}`,
+			fields: []string{"game_name"},
+		},
+		{
+			name:   "invalid result",
+			patch:  `{"price": 0, "languages": ["English"]}`,
+			fields: []string{"price", "languages"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyGamePatch(existing, []byte(tt.patch))
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			case tt.fields != nil:
+				var verr *domain.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("err = %v, want a validation error", err)
+				}
+				for _, f := range tt.fields {
+					if _, ok := verr.Fields[f]; !ok {
+						t.Errorf("no error for %s in %v", f, verr.Fields)
+					}
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+				tt.check(t, got)
+			}
+		})
+	}
+}
+
+func TestDiffGames(t *testing.T) {
+	parent := 9
+	base := domain.Game{
+		DeveloperID: 3,
+		Name:        "Hollow Pines",
+		Price:       19.99,
+		Languages:   []string{"en", "de"},
+		Genres:      []domain.Genre{{ID: 1}, {ID: 2}},
+	}
+
+	tests := []struct {
+		name   string
+		change func(g *domain.Game)
+		want   map[string]domain.FieldChange
+	}{
+		{
+			name:   "no change",
+			change: func(g *domain.Game) {},
+			want:   map[string]domain.FieldChange{},
+		},
+		{
+			name: "order of genres and languages does not matter",
+			change: func(g *domain.Game) {
+				g.Genres = []domain.Genre{{ID: 2}, {ID: 1}}
+				g.Languages = []string{"de", "en"}
+			},
+			want: map[string]domain.FieldChange{},
+		},
+		{
+			name: "scalar fields",
+			change: func(g *domain.Game) {
+				g.Name = "Hollow Pines II"
+				g.Price = 24.99
+			},
+			want: map[string]domain.FieldChange{
+				"game_name": {From: "Hollow Pines", To: "Hollow Pines II"},
+				"price":     {From: 19.99, To: 24.99},
+			},
+		},
+		{
+			name: "genres are compared by id",
+			change: func(g *domain.Game) {
+				g.Genres = []domain.Genre{{ID: 3}, {ID: 1}}
+			},
+			want: map[string]domain.FieldChange{
+				"genres": {From: []int{1, 2}, To: []int{1, 3}},
+			},
+		},
+		{
+			name: "parent game set",
+			change: func(g *domain.Game) {
+				g.ParentGameID = &parent
+			},
+			want: map[string]domain.FieldChange{
+				"parent_game_id": {From: (*int)(nil), To: &parent},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base
+			tt.change(&after)
+
+			if got := diffGames(base, after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustUnmarshal(t *testing.T, s string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// stockQueueSize bounds the changes waiting for the monitor. Beyond it a
+// change is dropped rather than holding up the purchase that made it.
+const stockQueueSize = 1024
+
+type stockEvent struct {
+	ctx               context.Context
+	gameID            int
+	previous, current int
+}
+
+// StockMonitor handles stock changes in the background: StockChanged only
+// queues the change, and Run checks the rule, alerts and restocks, so a slow
+// auto-restock never holds up the purchase that triggered it.
+type StockMonitor struct {
+	gameRepo       domain.GameRepository
+	notifier       domain.Notifier
+	contextTimeout time.Duration
+	events         chan stockEvent
+}
+
+func NewStockMonitor(g domain.GameRepository, n domain.Notifier, timeout time.Duration) *StockMonitor {
+	return &StockMonitor{
+		gameRepo:       g,
+		notifier:       n,
+		contextTimeout: timeout,
+		events:         make(chan stockEvent, stockQueueSize),
+	}
+}
+
+// StockChanged queues the change. ctx only lends its trace and request
+// attributes; the change is still handled after the request has ended.
+func (m *StockMonitor) StockChanged(ctx context.Context, gameID int, previous, current int) {
+	select {
+	case m.events <- stockEvent{ctx: context.WithoutCancel(ctx), gameID: gameID, previous: previous, current: current}:
+	default:
+		slog.ErrorContext(ctx, "stock: monitor queue full, change dropped", "game_id", gameID, "previous", previous, "current", current)
+	}
+}
+
+// Run handles queued changes until ctx is cancelled, then handles whatever
+// is still queued and returns.
+func (m *StockMonitor) Run(ctx context.Context) {
+	for {
+		select {
+		case e := <-m.events:
+			m.handle(e)
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-m.events:
+					m.handle(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (m *StockMonitor) handle(e stockEvent) {
+	ctx, cancel := context.WithTimeout(e.ctx, m.contextTimeout)
+	defer cancel()
+	m.check(ctx, e.gameID, e.previous, e.current)
+}
+
+// check alerts the publisher when stock drops to or below the game's
+// threshold and, if the rule asks for it, tops the stock back up. Only the
+// change that crosses the threshold triggers anything, so a game sitting
+// below it does not alert on every sale.
+func (m *StockMonitor) check(ctx context.Context, gameID int, previous, current int) {
+	ctx, span := tracer.Start(ctx, "StockMonitor.StockChanged")
+	defer span.End()
+	rule, err := m.gameRepo.GetStockRule(ctx, gameID)
+	if err != nil {
+		slog.ErrorContext(ctx, "stock: failed to load rule", "game_id", gameID, "err", err)
+		return
+	}
+	if rule.LowStockThreshold == 0 || previous <= rule.LowStockThreshold || current > rule.LowStockThreshold {
+		return
+	}
+
+	game, err := m.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		slog.ErrorContext(ctx, "stock: failed to load game", "game_id", gameID, "err", err)
+		return
+	}
+	publisherUserID, err := m.gameRepo.GetPublisherUserID(ctx, game.PublisherID)
+	if err != nil {
+		slog.ErrorContext(ctx, "stock: failed to find publisher", "game_id", gameID, "err", err)
+		return
+	}
+
+	message := fmt.Sprintf("%s has %d copies left (threshold %d)", game.Name, current, rule.LowStockThreshold)
+	if rule.AutoRestockAmount > 0 {
+		err := m.gameRepo.UpdateStock(ctx, domain.StockChange{
+			GameID: gameID,
+			Change: rule.AutoRestockAmount,
+			Reason: domain.StockReasonAutoRestock,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "stock: auto restock failed", "game_id", gameID, "err", err)
+			message += "; automatic restock failed"
+		} else {
+			message += fmt.Sprintf("; automatically restocked %d copies", rule.AutoRestockAmount)
+		}
+	}
+
+	err = m.notifier.Notify(ctx, domain.Notification{
+		UserID:  publisherUserID,
+		Type:    domain.NotificationLowStock,
+		Title:   fmt.Sprintf("%s is running low", game.Name),
+		Message: message,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "stock: failed to notify publisher", "game_id", gameID, "err", err)
+	}
+}
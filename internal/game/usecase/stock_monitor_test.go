@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"testing"
+	"time"
+)
+
+// restockingRepo has a rule that alerts below 3 copies and restocks 10.
+type restockingRepo struct {
+	domain.GameRepository
+	changes chan domain.StockChange
+}
+
+func (r *restockingRepo) GetStockRule(ctx context.Context, gameID int) (domain.StockRule, error) {
+	return domain.StockRule{GameID: gameID, LowStockThreshold: 3, AutoRestockAmount: 10}, nil
+}
+
+func (r *restockingRepo) GetByID(ctx context.Context, id int) (domain.Game, error) {
+	return domain.Game{ID: id, PublisherID: 1, Name: "Tetris"}, nil
+}
+
+func (r *restockingRepo) GetPublisherUserID(ctx context.Context, publisherID int) (int, error) {
+	return 99, nil
+}
+
+func (r *restockingRepo) UpdateStock(ctx context.Context, change domain.StockChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.changes <- change
+	return nil
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(ctx context.Context, n domain.Notification) error { return nil }
+
+func TestStockMonitorOutlivesTheRequest(t *testing.T) {
+	repo := &restockingRepo{changes: make(chan domain.StockChange, 1)}
+	m := NewStockMonitor(repo, nopNotifier{}, time.Minute)
+
+	// The purchase request has ended by the time the change is handled.
+	reqCtx, endRequest := context.WithCancel(context.Background())
+	m.StockChanged(reqCtx, 7, 4, 3)
+	endRequest()
+
+	select {
+	case change := <-repo.changes:
+		t.Fatalf("StockChanged restocked synchronously: %+v", change)
+	default:
+	}
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go m.Run(workerCtx)
+
+	select {
+	case change := <-repo.changes:
+		if change.GameID != 7 || change.Change != 10 || change.Reason != domain.StockReasonAutoRestock {
+			t.Errorf("restock = %+v", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the queued change was not handled")
+	}
+}
+
+func TestStockMonitorDrainsOnStop(t *testing.T) {
+	repo := &restockingRepo{changes: make(chan domain.StockChange, 2)}
+	m := NewStockMonitor(repo, nopNotifier{}, time.Minute)
+	m.StockChanged(context.Background(), 1, 4, 3)
+	m.StockChanged(context.Background(), 2, 4, 3)
+
+	ctx, stop := context.WithCancel(context.Background())
+	stop()
+	m.Run(ctx)
+
+	if len(repo.changes) != 2 {
+		t.Errorf("%d of 2 queued changes handled before Run returned", len(repo.changes))
+	}
+}
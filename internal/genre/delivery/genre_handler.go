@@ -12,10 +12,12 @@ type GenreHandler struct {
     Usecase domain.GenreUsecase
 }
 
-func NewGenreHandler(r *gin.Engine, us domain.GenreUsecase, jwtSecret string) {
+func NewGenreHandler(r gin.IRouter, us domain.GenreUsecase, jwtSecret string) {
     handler := &GenreHandler{Usecase: us}
 
     r.GET("/genres", handler.Fetch)
+    r.GET("/genres/tree", handler.FetchTree)
+    r.GET("/genres/:id", handler.Get)
 
     adminOnly := r.Group("/genres")
     adminOnly.Use(middleware.AuthMiddleware(jwtSecret))
@@ -24,21 +26,51 @@ func NewGenreHandler(r *gin.Engine, us domain.GenreUsecase, jwtSecret string) {
         adminOnly.POST("", handler.Create)
         adminOnly.PUT("/:id", handler.Update)
         adminOnly.DELETE("/:id", handler.Delete)
+        adminOnly.POST("/:id/merge-into/:target", handler.MergeInto)
     }
 }
 
 func (h *GenreHandler) Fetch(c *gin.Context) {
-    res, _ := h.Usecase.GetAll(c.Request.Context())
+    res, err := h.Usecase.GetAll(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    if res == nil {
+        res = []domain.Genre{}
+    }
+    c.JSON(http.StatusOK, res)
+}
+
+func (h *GenreHandler) FetchTree(c *gin.Context) {
+    res, err := h.Usecase.GetTree(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, res)
+}
+
+// Get accepts either the numeric ID or the slug, e.g. /genres/real-time-strategy.
+func (h *GenreHandler) Get(c *gin.Context) {
+    res, err := h.Usecase.Get(c.Request.Context(), c.Param("id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
     c.JSON(http.StatusOK, res)
 }
 
 func (h *GenreHandler) Create(c *gin.Context) {
     var g domain.Genre
     if err := c.ShouldBindJSON(&g); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(domain.ErrInvalidBody.Wrap(err))
+        return
+    }
+    if err := h.Usecase.Create(c.Request.Context(), &g); err != nil {
+        c.Error(err)
         return
     }
-    h.Usecase.Create(c.Request.Context(), &g)
     c.JSON(http.StatusCreated, g)
 }
 
@@ -46,16 +78,36 @@ func (h *GenreHandler) Update(c *gin.Context) {
     id, _ := strconv.Atoi(c.Param("id"))
     var g domain.Genre
     if err := c.ShouldBindJSON(&g); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(domain.ErrInvalidBody.Wrap(err))
         return
     }
     g.ID = id
-    h.Usecase.Update(c.Request.Context(), &g)
+    if err := h.Usecase.Update(c.Request.Context(), &g); err != nil {
+        c.Error(err)
+        return
+    }
     c.JSON(http.StatusOK, g)
 }
 
 func (h *GenreHandler) Delete(c *gin.Context) {
     id, _ := strconv.Atoi(c.Param("id"))
-    h.Usecase.Delete(c.Request.Context(), id)
+    force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
+
+    if err := h.Usecase.Delete(c.Request.Context(), id, force); err != nil {
+        c.Error(err)
+        return
+    }
     c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+func (h *GenreHandler) MergeInto(c *gin.Context) {
+    sourceID, _ := strconv.Atoi(c.Param("id"))
+    targetID, _ := strconv.Atoi(c.Param("target"))
+
+    res, err := h.Usecase.MergeInto(c.Request.Context(), sourceID, targetID)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, res)
+}
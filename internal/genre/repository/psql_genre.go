@@ -4,6 +4,8 @@ import (
     "context"
     "cool-games/internal/domain"
     "database/sql"
+
+    "github.com/lib/pq"
 )
 
 type psqlGenreRepository struct {
@@ -14,30 +16,113 @@ func NewPsqlGenreRepository(db *sql.DB) domain.GenreRepository {
     return &psqlGenreRepository{db}
 }
 
+const genreColumns = "id, genre_name, slug, parent_id"
+
 func (m *psqlGenreRepository) Fetch(ctx context.Context) ([]domain.Genre, error) {
-    rows, err := m.db.QueryContext(ctx, "SELECT id, genre_name FROM genres")
+    rows, err := m.db.QueryContext(ctx, "SELECT "+genreColumns+" FROM genres ORDER BY genre_name")
     if err != nil { return nil, err }
     defer rows.Close()
 
     var res []domain.Genre
     for rows.Next() {
         var g domain.Genre
-        if err := rows.Scan(&g.ID, &g.Name); err != nil { return nil, err }
+        if err := rows.Scan(&g.ID, &g.Name, &g.Slug, &g.ParentID); err != nil { return nil, err }
         res = append(res, g)
     }
-    return res, nil
+    return res, rows.Err()
+}
+
+func (m *psqlGenreRepository) GetByID(ctx context.Context, id int) (domain.Genre, error) {
+    return m.getOne(ctx, "SELECT "+genreColumns+" FROM genres WHERE id = $1", id)
+}
+
+func (m *psqlGenreRepository) GetBySlug(ctx context.Context, slug string) (domain.Genre, error) {
+    return m.getOne(ctx, "SELECT "+genreColumns+" FROM genres WHERE slug = $1", slug)
+}
+
+func (m *psqlGenreRepository) getOne(ctx context.Context, query string, arg interface{}) (domain.Genre, error) {
+    var g domain.Genre
+    err := m.db.QueryRowContext(ctx, query, arg).Scan(&g.ID, &g.Name, &g.Slug, &g.ParentID)
+    if err == sql.ErrNoRows { return domain.Genre{}, domain.ErrGenreNotFound }
+    return g, err
 }
 
 func (m *psqlGenreRepository) Store(ctx context.Context, g *domain.Genre) error {
-    return m.db.QueryRowContext(ctx, "INSERT INTO genres (genre_name) VALUES ($1) RETURNING id", g.Name).Scan(&g.ID)
+    err := m.db.QueryRowContext(ctx, "INSERT INTO genres (genre_name, slug, parent_id) VALUES ($1, $2, $3) RETURNING id", g.Name, g.Slug, g.ParentID).Scan(&g.ID)
+    return uniqueViolation(err)
 }
 
 func (m *psqlGenreRepository) Update(ctx context.Context, g *domain.Genre) error {
-    _, err := m.db.ExecContext(ctx, "UPDATE genres SET genre_name = $1 WHERE id = $2", g.Name, g.ID)
+    res, err := m.db.ExecContext(ctx, "UPDATE genres SET genre_name = $1, slug = $2, parent_id = $3 WHERE id = $4", g.Name, g.Slug, g.ParentID, g.ID)
+    if err != nil { return uniqueViolation(err) }
+    if n, _ := res.RowsAffected(); n == 0 { return domain.ErrGenreNotFound }
+    return nil
+}
+
+// Delete refuses a genre that games or subgenres still use unless force is
+// set. Forcing moves the subgenres up to the deleted genre's parent and takes
+// the genre off its games, bumping their version so stale edits are caught.
+func (m *psqlGenreRepository) Delete(ctx context.Context, id int, force bool) error {
+    tx, err := m.db.BeginTx(ctx, nil)
+    if err != nil { return err }
+    defer tx.Rollback()
+
+    var parentID *int
+    err = tx.QueryRowContext(ctx, "SELECT parent_id FROM genres WHERE id = $1 FOR UPDATE", id).Scan(&parentID)
+    if err == sql.ErrNoRows { return domain.ErrGenreNotFound }
+    if err != nil { return err }
+
+    var inUse bool
+    err = tx.QueryRowContext(ctx, `
+        SELECT EXISTS (SELECT 1 FROM game_genres WHERE genre_id = $1)
+            OR EXISTS (SELECT 1 FROM genres WHERE parent_id = $1)`, id).Scan(&inUse)
+    if err != nil { return err }
+    if inUse && !force { return domain.ErrGenreInUse }
+
+    if _, err := tx.ExecContext(ctx, "UPDATE genres SET parent_id = $1 WHERE parent_id = $2", parentID, id); err != nil { return err }
+    if err := touchGames(ctx, tx, id); err != nil { return err }
+    if _, err := tx.ExecContext(ctx, "DELETE FROM game_genres WHERE genre_id = $1", id); err != nil { return err }
+    if _, err := tx.ExecContext(ctx, "DELETE FROM genres WHERE id = $1", id); err != nil { return err }
+    return tx.Commit()
+}
+
+// MergeInto moves every game and subgenre of source to target and deletes
+// source, in one transaction.
+func (m *psqlGenreRepository) MergeInto(ctx context.Context, sourceID, targetID int) error {
+    tx, err := m.db.BeginTx(ctx, nil)
+    if err != nil { return err }
+    defer tx.Rollback()
+
+    rows, err := tx.QueryContext(ctx, "SELECT id FROM genres WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", sourceID, targetID)
+    if err != nil { return err }
+    locked := 0
+    for rows.Next() { locked++ }
+    rows.Close()
+    if err := rows.Err(); err != nil { return err }
+    if locked != 2 { return domain.ErrGenreNotFound }
+
+    if _, err := tx.ExecContext(ctx, "UPDATE genres SET parent_id = $1 WHERE parent_id = $2", targetID, sourceID); err != nil { return err }
+    if err := touchGames(ctx, tx, sourceID); err != nil { return err }
+    _, err = tx.ExecContext(ctx, `
+        INSERT INTO game_genres (game_id, genre_id)
+        SELECT game_id, $1 FROM game_genres WHERE genre_id = $2
+        ON CONFLICT DO NOTHING`, targetID, sourceID)
+    if err != nil { return err }
+    if _, err := tx.ExecContext(ctx, "DELETE FROM game_genres WHERE genre_id = $1", sourceID); err != nil { return err }
+    if _, err := tx.ExecContext(ctx, "DELETE FROM genres WHERE id = $1", sourceID); err != nil { return err }
+    return tx.Commit()
+}
+
+// touchGames bumps the version of every game filed under genreID, since its
+// genre list is about to change.
+func touchGames(ctx context.Context, tx *sql.Tx, genreID int) error {
+    _, err := tx.ExecContext(ctx, `
+        UPDATE games SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id IN (SELECT game_id FROM game_genres WHERE genre_id = $1)`, genreID)
     return err
 }
 
-func (m *psqlGenreRepository) Delete(ctx context.Context, id int) error {
-    _, err := m.db.ExecContext(ctx, "DELETE FROM genres WHERE id = $1", id)
+func uniqueViolation(err error) error {
+    if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { return domain.ErrGenreExists }
     return err
-}
\ No newline at end of file
+}
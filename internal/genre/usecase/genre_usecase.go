@@ -3,9 +3,15 @@ package usecase
 import (
 	"context"
 	"cool-games/internal/domain"
+	"strconv"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("cool-games/internal/genre/usecase")
+
 type genreUsecase struct {
 	genreRepo      domain.GenreRepository
 	contextTimeout time.Duration
@@ -19,25 +25,190 @@ func NewGenreUsecase(repo domain.GenreRepository, timeout time.Duration) domain.
 }
 
 func (u *genreUsecase) GetAll(ctx context.Context) ([]domain.Genre, error) {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.GetAll")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 	return u.genreRepo.Fetch(c)
 }
 
+// GetTree nests every genre under its parent, roots first, for navigation.
+func (u *genreUsecase) GetTree(ctx context.Context) ([]domain.Genre, error) {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.GetTree")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	genres, err := u.genreRepo.Fetch(c)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[int][]domain.Genre{}
+	var roots []domain.Genre
+	for _, g := range genres {
+		if g.ParentID == nil {
+			roots = append(roots, g)
+		} else {
+			children[*g.ParentID] = append(children[*g.ParentID], g)
+		}
+	}
+
+	var build func(g domain.Genre) domain.Genre
+	build = func(g domain.Genre) domain.Genre {
+		for _, child := range children[g.ID] {
+			g.Children = append(g.Children, build(child))
+		}
+		return g
+	}
+
+	tree := []domain.Genre{}
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree, nil
+}
+
+// Get looks a genre up by numeric ID or by slug.
+func (u *genreUsecase) Get(ctx context.Context, idOrSlug string) (domain.Genre, error) {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.Get")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if id, err := strconv.Atoi(idOrSlug); err == nil {
+		return u.genreRepo.GetByID(c, id)
+	}
+	return u.genreRepo.GetBySlug(c, idOrSlug)
+}
+
 func (u *genreUsecase) Create(ctx context.Context, genre *domain.Genre) error {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.Create")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
+
+	genre.ID = 0
+	if err := u.prepare(c, genre, ""); err != nil {
+		return err
+	}
 	return u.genreRepo.Store(c, genre)
 }
 
 func (u *genreUsecase) Update(ctx context.Context, genre *domain.Genre) error {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.Update")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
+
+	existing, err := u.genreRepo.GetByID(c, genre.ID)
+	if err != nil {
+		return err
+	}
+	if err := u.prepare(c, genre, existing.Slug); err != nil {
+		return err
+	}
 	return u.genreRepo.Update(c, genre)
 }
 
-func (u *genreUsecase) Delete(ctx context.Context, id int) error {
+func (u *genreUsecase) Delete(ctx context.Context, id int, force bool) error {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.Delete")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.genreRepo.Delete(c, id, force)
+}
+
+// MergeInto folds source into target, e.g. a duplicate "Roguelike" genre into
+// "Rogue-like", and returns the surviving genre.
+func (u *genreUsecase) MergeInto(ctx context.Context, sourceID, targetID int) (domain.Genre, error) {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.MergeInto")
+	defer span.End()
 	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
-	return u.genreRepo.Delete(c, id)
-}
\ No newline at end of file
+
+	if sourceID == targetID {
+		return domain.Genre{}, domain.ErrGenreMergeTarget
+	}
+	target, err := u.genreRepo.GetByID(c, targetID)
+	if err != nil {
+		return domain.Genre{}, err
+	}
+	if _, err := u.genreRepo.GetByID(c, sourceID); err != nil {
+		return domain.Genre{}, err
+	}
+
+	// Merging into a subgenre would leave that subgenre as its own ancestor.
+	if err := u.walkAncestors(c, target.ParentID, func(id int) error {
+		if id == sourceID {
+			return domain.ErrGenreMergeTarget
+		}
+		return nil
+	}); err != nil {
+		return domain.Genre{}, err
+	}
+
+	if err := u.genreRepo.MergeInto(c, sourceID, targetID); err != nil {
+		return domain.Genre{}, err
+	}
+	return u.genreRepo.GetByID(c, targetID)
+}
+
+// prepare validates the name, fills in the slug (keeping currentSlug unless a
+// new one is given) and checks the parent.
+func (u *genreUsecase) prepare(ctx context.Context, genre *domain.Genre, currentSlug string) error {
+	genre.Name = strings.TrimSpace(genre.Name)
+	if genre.Name == "" {
+		return domain.ErrGenreNameMissing
+	}
+
+	switch {
+	case genre.Slug != "":
+		genre.Slug = strings.ToLower(genre.Slug)
+	case currentSlug != "":
+		genre.Slug = currentSlug
+	default:
+		genre.Slug = domain.Slugify(genre.Name)
+	}
+	if !domain.ValidSlug(genre.Slug) {
+		return domain.ErrInvalidSlug
+	}
+
+	return u.checkParent(ctx, genre)
+}
+
+// checkParent makes sure the parent exists and, for an existing genre, is
+// not the genre itself or below it, which would cut the subtree off the tree.
+func (u *genreUsecase) checkParent(ctx context.Context, genre *domain.Genre) error {
+	err := u.walkAncestors(ctx, genre.ParentID, func(id int) error {
+		if genre.ID != 0 && id == genre.ID {
+			return domain.ErrGenreCycle
+		}
+		return nil
+	})
+	if err == domain.ErrGenreNotFound {
+		return domain.ErrParentNotFound
+	}
+	return err
+}
+
+// walkAncestors calls visit for start and every genre above it.
+func (u *genreUsecase) walkAncestors(ctx context.Context, start *int, visit func(id int) error) error {
+	seen := map[int]bool{}
+	for id := start; id != nil; {
+		if seen[*id] {
+			return domain.ErrGenreCycle
+		}
+		seen[*id] = true
+		if err := visit(*id); err != nil {
+			return err
+		}
+
+		g, err := u.genreRepo.GetByID(ctx, *id)
+		if err != nil {
+			return err
+		}
+		id = g.ParentID
+	}
+	return nil
+}
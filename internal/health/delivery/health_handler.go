@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const pingTimeout = 2 * time.Second
+
+// Pinger is satisfied by *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// HealthHandler serves the load balancer probes. /healthz only says the
+// process is up; /readyz says it should receive traffic.
+type HealthHandler struct {
+	db       Pinger
+	draining atomic.Bool
+}
+
+func NewHealthHandler(r gin.IRouter, db Pinger) *HealthHandler {
+	handler := &HealthHandler{db: db}
+
+	r.GET("/healthz", handler.Live)
+	r.GET("/readyz", handler.Ready)
+	return handler
+}
+
+// Drain makes /readyz fail from now on, so the load balancer stops sending
+// new requests while in-flight ones finish.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.Error(domain.ErrDraining)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), pingTimeout)
+	defer cancel()
+	if err := h.db.PingContext(ctx); err != nil {
+		slog.WarnContext(ctx, "readiness check failed", "err", err)
+		c.Error(domain.ErrDatabaseDown)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
@@ -0,0 +1,128 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"cool-games/internal/media/usecase"
+	"cool-games/internal/middleware"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler struct {
+	Usecase domain.MediaUsecase
+	Blobs   domain.BlobStore
+}
+
+// NewMediaHandler registers the upload endpoints under /games/:id/media.
+func NewMediaHandler(r gin.IRouter, us domain.MediaUsecase, jwtSecret string) {
+	handler := &MediaHandler{Usecase: us}
+
+	r.GET("/games/:id/media", handler.Fetch)
+
+	protected := r.Group("/games/:id/media")
+	protected.Use(middleware.AuthMiddleware(jwtSecret))
+	{
+		protected.POST("", middleware.RoleBlock("publisher"), handler.Upload)
+		protected.DELETE("/:mediaId", middleware.RoleBlock("publisher"), handler.Delete)
+	}
+}
+
+// NewMediaFileHandler serves stored blobs from /media. Their URLs are saved
+// with the media rows, so this stays outside the versioned API.
+func NewMediaFileHandler(r gin.IRouter, blobs domain.BlobStore) {
+	handler := &MediaHandler{Blobs: blobs}
+
+	r.GET("/media/*key", handler.Serve)
+}
+
+func (h *MediaHandler) Fetch(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+
+	res, err := h.Usecase.GetByGame(c.Request.Context(), gameID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Upload expects multipart/form-data with a "file" part and a "kind" field
+// (cover or screenshot).
+func (h *MediaHandler) Upload(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, usecase.MaxUploadBytes+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.Error(domain.ErrMediaTooLarge)
+			return
+		}
+		c.Error(domain.Invalid(`a file part named "file" is required`))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	kind := c.DefaultPostForm("kind", domain.MediaKindScreenshot)
+	res, err := h.Usecase.Upload(c.Request.Context(), gameID, kind, file, userID, role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *MediaHandler) Delete(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+	mediaID, _ := strconv.Atoi(c.Param("mediaId"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	if err := h.Usecase.Delete(c.Request.Context(), gameID, mediaID, userID, role); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MediaHandler) Serve(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	blob, err := h.Blobs.Open(c.Request.Context(), key)
+	if err != nil {
+		c.Error(domain.ErrMediaNotFound)
+		return
+	}
+	defer blob.Close()
+
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		c.Header("Content-Type", ct)
+	}
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	if rs, ok := blob.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, path.Base(key), time.Time{}, rs)
+		return
+	}
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, blob)
+}
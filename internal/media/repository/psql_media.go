@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+	"errors"
+)
+
+type psqlMediaRepository struct {
+	db *sql.DB
+}
+
+func NewPsqlMediaRepository(db *sql.DB) domain.MediaRepository {
+	return &psqlMediaRepository{db: db}
+}
+
+const mediaColumns = `id, game_id, kind, url, thumbnail_url, content_type, size_bytes, width, height, storage_key, thumbnail_key, created_at`
+
+func scanMedia(row interface{ Scan(...interface{}) error }) (domain.GameMedia, error) {
+	var m domain.GameMedia
+	err := row.Scan(&m.ID, &m.GameID, &m.Kind, &m.URL, &m.ThumbnailURL, &m.ContentType,
+		&m.SizeBytes, &m.Width, &m.Height, &m.StorageKey, &m.ThumbnailKey, &m.CreatedAt)
+	return m, err
+}
+
+func (r *psqlMediaRepository) Store(ctx context.Context, m *domain.GameMedia) error {
+	query := `
+		INSERT INTO game_media (game_id, kind, url, thumbnail_url, content_type, size_bytes, width, height, storage_key, thumbnail_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, m.GameID, m.Kind, m.URL, m.ThumbnailURL, m.ContentType,
+		m.SizeBytes, m.Width, m.Height, m.StorageKey, m.ThumbnailKey).Scan(&m.ID, &m.CreatedAt)
+}
+
+func (r *psqlMediaRepository) GetByID(ctx context.Context, id int) (domain.GameMedia, error) {
+	m, err := scanMedia(r.db.QueryRowContext(ctx, `SELECT `+mediaColumns+` FROM game_media WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.GameMedia{}, domain.ErrMediaNotFound
+	}
+	return m, err
+}
+
+func (r *psqlMediaRepository) FetchByGame(ctx context.Context, gameID int) ([]domain.GameMedia, error) {
+	query := `SELECT ` + mediaColumns + ` FROM game_media WHERE game_id = $1
+              ORDER BY CASE kind WHEN 'cover' THEN 0 ELSE 1 END, created_at`
+	rows, err := r.db.QueryContext(ctx, query, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	media := []domain.GameMedia{}
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, m)
+	}
+	return media, rows.Err()
+}
+
+func (r *psqlMediaRepository) CountByKind(ctx context.Context, gameID int, kind string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM game_media WHERE game_id = $1 AND kind = $2`, gameID, kind).Scan(&count)
+	return count, err
+}
+
+func (r *psqlMediaRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM game_media WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrMediaNotFound
+	}
+	return nil
+}
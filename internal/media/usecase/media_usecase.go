@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"cool-games/internal/domain"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("cool-games/internal/media/usecase")
+
+const (
+	MaxUploadBytes = 5 << 20
+	maxScreenshots = 10
+	maxPixels      = 40_000_000
+)
+
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+type mediaUsecase struct {
+	mediaRepo      domain.MediaRepository
+	gameRepo       domain.GameRepository
+	blobs          domain.BlobStore
+	contextTimeout time.Duration
+}
+
+func NewMediaUsecase(m domain.MediaRepository, g domain.GameRepository, blobs domain.BlobStore, timeout time.Duration) domain.MediaUsecase {
+	return &mediaUsecase{
+		mediaRepo:      m,
+		gameRepo:       g,
+		blobs:          blobs,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *mediaUsecase) authorize(ctx context.Context, gameID int, requesterID int, role string) error {
+	game, err := u.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if role == "admin" {
+		return nil
+	}
+	pubID, err := u.gameRepo.GetPublisherIDByUserID(ctx, requesterID)
+	if err != nil || game.PublisherID != pubID {
+		return domain.ErrUnauthorizedAction
+	}
+	return nil
+}
+
+// Upload checks the real content type of the file (not what the client
+// claims), stores the original and a thumbnail, and records both. A new cover
+// replaces the previous one.
+func (u *mediaUsecase) Upload(ctx context.Context, gameID int, kind string, file io.Reader, requesterID int, role string) (domain.GameMedia, error) {
+	ctx, span := tracer.Start(ctx, "MediaUsecase.Upload")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if kind != domain.MediaKindCover && kind != domain.MediaKindScreenshot {
+		return domain.GameMedia{}, domain.ErrInvalidMediaKind
+	}
+	if err := u.authorize(c, gameID, requesterID, role); err != nil {
+		return domain.GameMedia{}, err
+	}
+
+	if kind == domain.MediaKindScreenshot {
+		count, err := u.mediaRepo.CountByKind(c, gameID, kind)
+		if err != nil {
+			return domain.GameMedia{}, err
+		}
+		if count >= maxScreenshots {
+			return domain.GameMedia{}, domain.ErrTooManyScreenshots
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxUploadBytes+1))
+	if err != nil {
+		return domain.GameMedia{}, err
+	}
+	if len(data) > MaxUploadBytes {
+		return domain.GameMedia{}, domain.ErrMediaTooLarge
+	}
+
+	contentType := mimetype.Detect(data).String()
+	ext, ok := allowedImageTypes[contentType]
+	if !ok {
+		return domain.GameMedia{}, domain.ErrUnsupportedMedia
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width*cfg.Height > maxPixels {
+		return domain.GameMedia{}, domain.ErrUnsupportedMedia
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return domain.GameMedia{}, domain.ErrUnsupportedMedia
+	}
+
+	asPNG := contentType != "image/jpeg"
+	thumb, err := makeThumbnail(img, asPNG)
+	if err != nil {
+		return domain.GameMedia{}, err
+	}
+	thumbExt, thumbType := ".jpg", "image/jpeg"
+	if asPNG {
+		thumbExt, thumbType = ".png", "image/png"
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return domain.GameMedia{}, err
+	}
+	key := fmt.Sprintf("games/%d/%s%s", gameID, name, ext)
+	thumbKey := fmt.Sprintf("games/%d/%s_thumb%s", gameID, name, thumbExt)
+
+	if err := u.blobs.Put(c, key, bytes.NewReader(data), contentType); err != nil {
+		return domain.GameMedia{}, err
+	}
+	if err := u.blobs.Put(c, thumbKey, bytes.NewReader(thumb), thumbType); err != nil {
+		u.removeBlobs(c, key)
+		return domain.GameMedia{}, err
+	}
+
+	var previousCovers []domain.GameMedia
+	if kind == domain.MediaKindCover {
+		existing, err := u.mediaRepo.FetchByGame(c, gameID)
+		if err != nil {
+			u.removeBlobs(c, key, thumbKey)
+			return domain.GameMedia{}, err
+		}
+		for _, m := range existing {
+			if m.Kind == domain.MediaKindCover {
+				previousCovers = append(previousCovers, m)
+			}
+		}
+	}
+
+	media := domain.GameMedia{
+		GameID:       gameID,
+		Kind:         kind,
+		URL:          u.blobs.URL(key),
+		ThumbnailURL: u.blobs.URL(thumbKey),
+		ContentType:  contentType,
+		SizeBytes:    int64(len(data)),
+		Width:        cfg.Width,
+		Height:       cfg.Height,
+		StorageKey:   key,
+		ThumbnailKey: thumbKey,
+	}
+	if err := u.mediaRepo.Store(c, &media); err != nil {
+		u.removeBlobs(c, key, thumbKey)
+		return domain.GameMedia{}, err
+	}
+
+	for _, old := range previousCovers {
+		if err := u.mediaRepo.Delete(c, old.ID); err == nil {
+			u.removeBlobs(c, old.StorageKey, old.ThumbnailKey)
+		}
+	}
+	return media, nil
+}
+
+func (u *mediaUsecase) GetByGame(ctx context.Context, gameID int) ([]domain.GameMedia, error) {
+	ctx, span := tracer.Start(ctx, "MediaUsecase.GetByGame")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.gameRepo.GetByID(c, gameID); err != nil {
+		return nil, err
+	}
+	return u.mediaRepo.FetchByGame(c, gameID)
+}
+
+func (u *mediaUsecase) Delete(ctx context.Context, gameID int, mediaID int, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "MediaUsecase.Delete")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if err := u.authorize(c, gameID, requesterID, role); err != nil {
+		return err
+	}
+
+	media, err := u.mediaRepo.GetByID(c, mediaID)
+	if err != nil {
+		return err
+	}
+	if media.GameID != gameID {
+		return domain.ErrMediaNotFound
+	}
+
+	if err := u.mediaRepo.Delete(c, mediaID); err != nil {
+		return err
+	}
+	u.removeBlobs(c, media.StorageKey, media.ThumbnailKey)
+	return nil
+}
+
+// removeBlobs is cleanup after the database already moved on, so failures
+// are only logged: an orphaned file is harmless, a lost row is not.
+func (u *mediaUsecase) removeBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := u.blobs.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "media: failed to delete blob", "key", key, "err", err)
+		}
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
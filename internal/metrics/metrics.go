@@ -0,0 +1,150 @@
+// Package metrics exports Prometheus metrics for HTTP traffic, the database
+// pool and business events. Everything is registered on the Metrics'
+// own registry, served by Handler.
+package metrics
+
+import (
+	"cool-games/internal/domain"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cool_games"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	httpInFlight     prometheus.Gauge
+	purchases        *prometheus.CounterVec
+	purchaseRevenue  *prometheus.CounterVec
+	purchaseFailures *prometheus.CounterVec
+	topUps           *prometheus.CounterVec
+	topUpAmount      *prometheus.CounterVec
+	registrations    *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"method", "route", "status"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests being served.",
+		}),
+		purchases: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "purchases_total",
+			Help:      "Completed purchases by kind (game or bundle) and currency.",
+		}, []string{"kind", "currency"}),
+		purchaseRevenue: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "purchase_revenue_total",
+			Help:      "Amount charged for completed purchases, in the currency of the label.",
+		}, []string{"kind", "currency"}),
+		purchaseFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "purchase_failures_total",
+			Help:      "Rejected or failed purchases by kind and reason, the error code.",
+		}, []string{"kind", "reason"}),
+		topUps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "topups_total",
+			Help:      "Wallet top-ups by currency.",
+		}, []string{"currency"}),
+		topUpAmount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "topup_amount_total",
+			Help:      "Amount added to wallets, in the currency of the label.",
+		}, []string{"currency"}),
+		registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "registrations_total",
+			Help:      "New accounts by role.",
+		}, []string{"role"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+		m.purchases, m.purchaseRevenue, m.purchaseFailures,
+		m.topUps, m.topUpAmount, m.registrations,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// WatchDB exports the pool statistics of db (open, in use and idle
+// connections, waits) as go_sql_* metrics labelled with name.
+func (m *Metrics) WatchDB(name string, db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RequestStarted and RequestDone bracket one HTTP request. route is the
+// route pattern, not the path, so IDs do not multiply the series.
+func (m *Metrics) RequestStarted() {
+	m.httpInFlight.Inc()
+}
+
+func (m *Metrics) RequestDone(method, route string, status int, elapsed time.Duration) {
+	m.httpInFlight.Dec()
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+	m.httpRequests.With(labels).Inc()
+	m.httpDuration.With(labels).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) PurchaseCompleted(kind string, amount float64, currency string) {
+	m.purchases.WithLabelValues(kind, currency).Inc()
+	m.purchaseRevenue.WithLabelValues(kind, currency).Add(amount)
+}
+
+func (m *Metrics) PurchaseFailed(kind string, err error) {
+	m.purchaseFailures.WithLabelValues(kind, reason(err)).Inc()
+}
+
+func (m *Metrics) BalanceToppedUp(amount float64, currency string) {
+	m.topUps.WithLabelValues(currency).Inc()
+	m.topUpAmount.WithLabelValues(currency).Add(amount)
+}
+
+func (m *Metrics) UserRegistered(role string) {
+	m.registrations.WithLabelValues(role).Inc()
+}
+
+// reason keeps the label set small: domain error codes are a fixed list,
+// anything else is internal.
+func reason(err error) string {
+	var verr *domain.ValidationError
+	var derr *domain.Error
+	switch {
+	case errors.As(err, &verr):
+		return "validation_failed"
+	case errors.As(err, &derr) && derr.Kind != domain.KindInternal:
+		return derr.Code
+	}
+	return string(domain.KindInternal)
+}
@@ -1,8 +1,9 @@
 package middleware
 
 import (
+	"cool-games/internal/domain"
 	"fmt"
-	"net/http"
+	"log/slog"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -13,7 +14,7 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			abortWithError(c, domain.ErrUnauthenticated)
 			return
 		}
 
@@ -31,8 +32,8 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 		})
 
 		if err != nil {
-			fmt.Printf("DEBUG [Auth]: JWT Parse Error: %v\n", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			slog.DebugContext(c.Request.Context(), "auth: token rejected", "err", err)
+			abortWithError(c, domain.ErrInvalidToken)
 			return
 		}
 
@@ -41,8 +42,8 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 			role, okRole := claims["role"].(string)
 
 			if !okUID || !okRole {
-				fmt.Println("DEBUG [Auth]: Token missing required claims (user_id or role)")
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+				slog.DebugContext(c.Request.Context(), "auth: token missing user_id or role claim")
+				abortWithError(c, domain.ErrInvalidToken)
 				return
 			}
 
@@ -50,16 +51,30 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 			c.Set("role", role)
 			c.Next()
 		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			abortWithError(c, domain.ErrInvalidToken)
 		}
 	}
 }
 
+// OptionalAuth identifies the caller when a valid token is sent and lets
+// anonymous requests through untouched. A token that is sent but invalid is
+// still rejected, so clients notice expired sessions.
+func OptionalAuth(secret string) gin.HandlerFunc {
+	required := AuthMiddleware(secret)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		required(c)
+	}
+}
+
 func RoleBlock(authorizedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Identity not found"})
+			abortWithError(c, domain.ErrUnauthenticated)
 			return
 		}
 
@@ -77,8 +92,12 @@ func RoleBlock(authorizedRoles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-			"error": fmt.Sprintf("Access denied. Role '%s' is not authorized for this action", roleStr),
-		})
+		abortWithError(c, domain.ErrRoleForbidden.WithDetails(map[string]interface{}{"role": roleStr}))
 	}
+}
+
+// abortWithError stops the chain and leaves err for ErrorHandler to render.
+func abortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
 }
\ No newline at end of file
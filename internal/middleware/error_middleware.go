@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"cool-games/internal/domain"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Problem is an RFC 7807 problem details body. Code repeats the domain error
+// code so clients can branch on it without parsing Type.
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code"`
+	Fields   map[string]string      `json:"fields,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+var kindStatus = map[domain.ErrorKind]int{
+	domain.KindMalformed:       http.StatusBadRequest,
+	domain.KindInvalid:         http.StatusUnprocessableEntity,
+	domain.KindUnauthenticated: http.StatusUnauthorized,
+	domain.KindForbidden:       http.StatusForbidden,
+	domain.KindNotFound:        http.StatusNotFound,
+	domain.KindConflict:        http.StatusConflict,
+	domain.KindPrecondition:    http.StatusPreconditionFailed,
+	domain.KindTooLarge:        http.StatusRequestEntityTooLarge,
+	domain.KindUnsupported:     http.StatusUnsupportedMediaType,
+	domain.KindUnavailable:     http.StatusServiceUnavailable,
+	domain.KindInternal:        http.StatusInternalServerError,
+}
+
+// ErrorHandler renders the last error a handler attached with c.Error as
+// application/problem+json. Internal errors are logged and their message is
+// not sent to the client. It must run before the auth middleware, which
+// reports through c.Error as well.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		err := c.Errors.Last().Err
+		problem := NewProblem(err)
+		problem.Instance = c.Request.URL.Path
+		if problem.Status == http.StatusInternalServerError {
+			slog.ErrorContext(c.Request.Context(), "request failed",
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"err", err)
+		}
+		// A streamed response may fail after its status went out.
+		if c.Writer.Written() {
+			return
+		}
+
+		// c.JSON keeps a Content-Type that is already set.
+		c.Header("Content-Type", "application/problem+json")
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// NewProblem describes err the way ErrorHandler sends it.
+func NewProblem(err error) Problem {
+	kind := domain.KindOf(err)
+	status := kindStatus[kind]
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   string(kind),
+		Detail: http.StatusText(status),
+	}
+
+	var verr *domain.ValidationError
+	var derr *domain.Error
+	switch {
+	case errors.As(err, &verr):
+		p.Code = "validation_failed"
+		p.Detail = verr.Error()
+		p.Fields = verr.Fields
+	case errors.As(err, &derr) && kind != domain.KindInternal:
+		p.Code = derr.Code
+		p.Detail = derr.Error()
+		p.Details = derr.Details
+	}
+	if p.Code != string(domain.KindInternal) {
+		p.Type = "/problems/" + p.Code
+	}
+	return p
+}
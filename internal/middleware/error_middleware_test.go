@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"cool-games/internal/domain"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNewProblemStatus(t *testing.T) {
+	tests := []struct {
+		kind   domain.ErrorKind
+		status int
+	}{
+		{domain.KindMalformed, http.StatusBadRequest},
+		{domain.KindInvalid, http.StatusUnprocessableEntity},
+		{domain.KindUnauthenticated, http.StatusUnauthorized},
+		{domain.KindForbidden, http.StatusForbidden},
+		{domain.KindNotFound, http.StatusNotFound},
+		{domain.KindConflict, http.StatusConflict},
+		{domain.KindPrecondition, http.StatusPreconditionFailed},
+		{domain.KindTooLarge, http.StatusRequestEntityTooLarge},
+		{domain.KindUnsupported, http.StatusUnsupportedMediaType},
+		{domain.KindUnavailable, http.StatusServiceUnavailable},
+		{domain.KindInternal, http.StatusInternalServerError},
+	}
+	if len(tests) != len(kindStatus) {
+		t.Fatalf("%d kinds tested, %d mapped", len(tests), len(kindStatus))
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			p := NewProblem(domain.NewError(tt.kind, "some_code", "some message"))
+			if p.Status != tt.status || p.Title != http.StatusText(tt.status) {
+				t.Errorf("status %d %q, want %d", p.Status, p.Title, tt.status)
+			}
+		})
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	verr := domain.NewValidationError()
+	verr.Add("price", "must be greater than 0")
+
+	tests := []struct {
+		name string
+		err  error
+		want Problem
+	}{
+		{
+			name: "domain error",
+			err:  fmt.Errorf("buying: %w", domain.ErrGameNotFound),
+			want: Problem{
+				Type:   "/problems/game_not_found",
+				Title:  "Not Found",
+				Status: http.StatusNotFound,
+				Detail: domain.ErrGameNotFound.Message,
+				Code:   "game_not_found",
+			},
+		},
+		{
+			name: "details are passed on",
+			err:  domain.ErrGameNotFound.WithDetails(map[string]interface{}{"game_id": 7}),
+			want: Problem{
+				Type:    "/problems/game_not_found",
+				Title:   "Not Found",
+				Status:  http.StatusNotFound,
+				Detail:  domain.ErrGameNotFound.Message,
+				Code:    "game_not_found",
+				Details: map[string]interface{}{"game_id": 7},
+			},
+		},
+		{
+			name: "validation error",
+			err:  verr,
+			want: Problem{
+				Type:   "/problems/validation_failed",
+				Title:  "Unprocessable Entity",
+				Status: http.StatusUnprocessableEntity,
+				Detail: verr.Error(),
+				Code:   "validation_failed",
+				Fields: verr.Fields,
+			},
+		},
+		{
+			name: "a body that does not bind is a bad request",
+			err:  domain.ErrInvalidBody.Wrap(errors.New("unexpected EOF")),
+			want: Problem{
+				Type:   "/problems/invalid_body",
+				Title:  "Bad Request",
+				Status: http.StatusBadRequest,
+				Detail: "invalid request body: unexpected EOF",
+				Code:   "invalid_body",
+			},
+		},
+		{
+			name: "unknown errors hide their message",
+			err:  errors.New("pq: connection refused"),
+			want: Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "Internal Server Error",
+				Code:   "internal",
+			},
+		},
+		{
+			name: "internal domain errors hide their message",
+			err:  domain.NewError(domain.KindInternal, "oops", "secret detail"),
+			want: Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "Internal Server Error",
+				Code:   "internal",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewProblem(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
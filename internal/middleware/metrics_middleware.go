@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"cool-games/internal/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records every request's latency and status under its route
+// pattern. Requests that match no route share the "unmatched" route.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.RequestStarted()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.RequestDone(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"cool-games/internal/metrics"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricsRouteLabel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		method, path string
+		route        string
+		status       int
+	}{
+		{http.MethodGet, "/v1/games/7", "/v1/games/:id", http.StatusOK},
+		{http.MethodGet, "/v1/games/8", "/v1/games/:id", http.StatusOK},
+		{http.MethodGet, "/games/7", "/games/:id", http.StatusOK},
+		{http.MethodGet, "/media/covers/a/b.png", "/media/*key", http.StatusOK},
+		{http.MethodGet, "/v1/nope/7", "unmatched", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			m := metrics.New()
+			r := gin.New()
+			r.Use(Metrics(m))
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			r.GET("/v1/games/:id", ok)
+			r.Group("").GET("/games/:id", ok)
+			r.GET("/media/*key", ok)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			body := scrape(t, m)
+			want := fmt.Sprintf(`cool_games_http_requests_total{method=%q,route=%q,status="%d"} 1`, tt.method, tt.route, tt.status)
+			if !strings.Contains(body, want) {
+				t.Errorf("no %s in\n%s", want, grep(body, "cool_games_http_requests_total"))
+			}
+			if tt.route != tt.path && strings.Contains(body, `route="`+tt.path+`"`) {
+				t.Errorf("the raw path %s became a route label", tt.path)
+			}
+			if !strings.Contains(body, "cool_games_http_requests_in_flight 0") {
+				t.Errorf("in-flight gauge not back to 0:\n%s", grep(body, "in_flight"))
+			}
+		})
+	}
+}
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("scrape: %d", w.Code)
+	}
+	return w.Body.String()
+}
+
+func grep(body, substr string) string {
+	var lines []string
+	for _, l := range strings.Split(body, "\n") {
+		if strings.Contains(l, substr) {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,12 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// TraceFilter keeps probes and scrapes out of traces.
+func TraceFilter(c *gin.Context) bool {
+	switch c.Request.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
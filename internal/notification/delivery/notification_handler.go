@@ -0,0 +1,110 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const streamHeartbeat = 30 * time.Second
+
+type NotificationHandler struct {
+	Usecase domain.NotificationUsecase
+}
+
+func NewNotificationHandler(r gin.IRouter, us domain.NotificationUsecase, jwtSecret string) {
+	handler := &NotificationHandler{Usecase: us}
+
+	notifications := r.Group("/me/notifications")
+	notifications.Use(middleware.AuthMiddleware(jwtSecret))
+	notifications.Use(middleware.RoleBlock("customer", "publisher"))
+	{
+		notifications.GET("", handler.Fetch)
+		notifications.GET("/stream", handler.Stream)
+		notifications.POST("/read-all", handler.MarkAllRead)
+		notifications.POST("/:id/read", handler.MarkRead)
+	}
+}
+
+func (h *NotificationHandler) Fetch(c *gin.Context) {
+	userID := c.MustGet("user_id").(int)
+	unreadOnly := c.Query("unread") == "true"
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	res, err := h.Usecase.GetNotifications(c.Request.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+
+	if err := h.Usecase.MarkRead(c.Request.Context(), userID, id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID := c.MustGet("user_id").(int)
+
+	if err := h.Usecase.MarkAllRead(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Stream pushes new notifications to the client as Server-Sent Events until
+// the client disconnects or the server shuts down. A ping event keeps idle
+// proxies from closing it.
+func (h *NotificationHandler) Stream(c *gin.Context) {
+	userID := c.MustGet("user_id").(int)
+
+	events, unsubscribe := h.Usecase.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// The server's WriteTimeout is sized for ordinary requests; a stream
+	// lives until the client leaves or the server shuts down.
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case n, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", n)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
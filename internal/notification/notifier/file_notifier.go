@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+type fileNotifier struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+type fileRecord struct {
+	domain.Notification
+	SentAt time.Time `json:"sent_at"`
+}
+
+// NewFileNotifier appends every notification as a JSON line to the file at
+// path, creating it if needed.
+func NewFileNotifier(path string) (domain.Notifier, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileNotifier{file: f}, nil
+}
+
+func (n *fileNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	line, err := json.Marshal(fileRecord{Notification: notification, SentAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.file.Write(append(line, '\n'))
+	return err
+}
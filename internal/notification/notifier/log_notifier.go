@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"log/slog"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier logs every notification. Meant for
+// local runs where nobody is listening for real deliveries.
+func NewLogNotifier() domain.Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	slog.InfoContext(ctx, "notification",
+		"user_id", notification.UserID,
+		"type", notification.Type,
+		"title", notification.Title,
+		"message", notification.Message)
+	return nil
+}
@@ -0,0 +1,27 @@
+package notifier
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"errors"
+)
+
+type multiNotifier struct {
+	notifiers []domain.Notifier
+}
+
+// NewMultiNotifier fans every notification out to all given notifiers and
+// reports the joined errors of the ones that failed.
+func NewMultiNotifier(notifiers ...domain.Notifier) domain.Notifier {
+	return &multiNotifier{notifiers: notifiers}
+}
+
+func (m *multiNotifier) Notify(ctx context.Context, n domain.Notification) error {
+	var errs []error
+	for _, notifier := range m.notifiers {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+)
+
+type psqlNotificationRepository struct {
+	db *sql.DB
+}
+
+func NewPsqlNotificationRepository(db *sql.DB) domain.NotificationRepository {
+	return &psqlNotificationRepository{db: db}
+}
+
+func (r *psqlNotificationRepository) Store(ctx context.Context, n *domain.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, type, title, message)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, n.UserID, n.Type, n.Title, n.Message).Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *psqlNotificationRepository) FetchByUser(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]domain.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, message, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND ($2 = FALSE OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Notification
+	for rows.Next() {
+		var n domain.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	return res, rows.Err()
+}
+
+func (r *psqlNotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *psqlNotificationRepository) MarkRead(ctx context.Context, userID int, id int) error {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *psqlNotificationRepository) MarkAllRead(ctx context.Context, userID int) error {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("cool-games/internal/notification/usecase")
+
+const subscriberBuffer = 16
+
+type notificationUsecase struct {
+	notificationRepo domain.NotificationRepository
+	contextTimeout   time.Duration
+
+	mu          sync.Mutex
+	subscribers map[int]map[chan domain.Notification]struct{}
+	closed      bool
+}
+
+// NewNotificationUsecase returns a usecase that persists notifications and
+// pushes them to every live subscriber of the recipient. It also satisfies
+// domain.Notifier, so other usecases can depend on it directly.
+func NewNotificationUsecase(repo domain.NotificationRepository, timeout time.Duration) domain.NotificationUsecase {
+	return &notificationUsecase{
+		notificationRepo: repo,
+		contextTimeout:   timeout,
+		subscribers:      make(map[int]map[chan domain.Notification]struct{}),
+	}
+}
+
+func (u *notificationUsecase) Notify(ctx context.Context, n domain.Notification) error {
+	ctx, span := tracer.Start(ctx, "NotificationUsecase.Notify")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if err := u.notificationRepo.Store(c, &n); err != nil {
+		return err
+	}
+
+	u.broadcast(n)
+	return nil
+}
+
+func (u *notificationUsecase) GetNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) (domain.NotificationList, error) {
+	ctx, span := tracer.Start(ctx, "NotificationUsecase.GetNotifications")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	items, err := u.notificationRepo.FetchByUser(c, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return domain.NotificationList{}, err
+	}
+	if items == nil {
+		items = []domain.Notification{}
+	}
+
+	unread, err := u.notificationRepo.CountUnread(c, userID)
+	if err != nil {
+		return domain.NotificationList{}, err
+	}
+
+	return domain.NotificationList{Items: items, UnreadCount: unread}, nil
+}
+
+func (u *notificationUsecase) MarkRead(ctx context.Context, userID int, id int) error {
+	ctx, span := tracer.Start(ctx, "NotificationUsecase.MarkRead")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.notificationRepo.MarkRead(c, userID, id)
+}
+
+func (u *notificationUsecase) MarkAllRead(ctx context.Context, userID int) error {
+	ctx, span := tracer.Start(ctx, "NotificationUsecase.MarkAllRead")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.notificationRepo.MarkAllRead(c, userID)
+}
+
+// Subscribe registers a live listener for userID. The returned function must
+// be called once the listener goes away; it closes the channel. After
+// CloseSubscribers the channel comes back already closed.
+func (u *notificationUsecase) Subscribe(userID int) (<-chan domain.Notification, func()) {
+	ch := make(chan domain.Notification, subscriberBuffer)
+
+	u.mu.Lock()
+	if u.closed {
+		u.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if u.subscribers[userID] == nil {
+		u.subscribers[userID] = make(map[chan domain.Notification]struct{})
+	}
+	u.subscribers[userID][ch] = struct{}{}
+	u.mu.Unlock()
+
+	return ch, func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		if _, ok := u.subscribers[userID][ch]; !ok {
+			return
+		}
+		delete(u.subscribers[userID], ch)
+		if len(u.subscribers[userID]) == 0 {
+			delete(u.subscribers, userID)
+		}
+		close(ch)
+	}
+}
+
+// CloseSubscribers closes every live channel and refuses new ones, so open
+// streams end and the server can shut down.
+func (u *notificationUsecase) CloseSubscribers() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.closed = true
+	for userID, chans := range u.subscribers {
+		for ch := range chans {
+			close(ch)
+		}
+		delete(u.subscribers, userID)
+	}
+}
+
+// broadcast never blocks: a subscriber that is not keeping up misses the live
+// event but still finds it in GET /me/notifications.
+func (u *notificationUsecase) broadcast(n domain.Notification) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for ch := range u.subscribers[n.UserID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
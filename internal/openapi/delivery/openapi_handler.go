@@ -0,0 +1,54 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// swaggerInitializer replaces the one shipped with Swagger UI, which points
+// at the petstore example.
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+type OpenAPIHandler struct {
+	spec   []byte
+	assets http.Handler
+}
+
+// NewOpenAPIHandler serves spec at /openapi.json and a bundled Swagger UI
+// for it under /docs/.
+func NewOpenAPIHandler(r gin.IRouter, spec []byte) {
+	handler := &OpenAPIHandler{
+		spec:   spec,
+		assets: http.FileServer(http.FS(swaggerFiles.FS)),
+	}
+
+	r.GET("/openapi.json", handler.Spec)
+	r.GET("/docs/*filepath", handler.Docs)
+}
+
+func (h *OpenAPIHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", h.spec)
+}
+
+func (h *OpenAPIHandler) Docs(c *gin.Context) {
+	if c.Param("filepath") == "/swagger-initializer.js" {
+		c.Data(http.StatusOK, "application/javascript", []byte(swaggerInitializer))
+		return
+	}
+	// Serve relative to wherever the group mounted /docs.
+	req := c.Request.Clone(c.Request.Context())
+	req.URL.Path = c.Param("filepath")
+	h.assets.ServeHTTP(c.Writer, req)
+}
@@ -0,0 +1,98 @@
+// Package openapi holds the OpenAPI 3 description of the HTTP API and checks
+// it against the routes the server actually registers.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// JSON is the document as served at /openapi.json.
+func JSON() ([]byte, error) {
+	spec, err := yaml.YAMLToJSON(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("openapi.yaml: %w", err)
+	}
+	return spec, nil
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Check reports every registered route the document does not describe, and
+// every documented operation no route serves. A route is a legacy alias, and
+// needs no entry of its own, when legacyPrefix followed by its path is
+// documented.
+func Check(routes gin.RoutesInfo, legacyPrefix string) error {
+	spec, err := JSON()
+	if err != nil {
+		return err
+	}
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return fmt.Errorf("openapi.yaml: %w", err)
+	}
+
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for _, method := range methods {
+			if _, ok := item[method]; ok {
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	var undocumented []string
+	served := map[string]bool{}
+	for _, route := range routes {
+		path := templatePath(route.Path)
+		if documented[route.Method+" "+legacyPrefix+path] {
+			continue
+		}
+		op := route.Method + " " + path
+		served[op] = true
+		if !documented[op] {
+			undocumented = append(undocumented, op)
+		}
+	}
+	var stale []string
+	for op := range documented {
+		if !served[op] {
+			stale = append(stale, op)
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(stale)
+
+	var errs []error
+	if len(undocumented) > 0 {
+		errs = append(errs, fmt.Errorf("routes missing from openapi.yaml: %s", strings.Join(undocumented, ", ")))
+	}
+	if len(stale) > 0 {
+		errs = append(errs, fmt.Errorf("openapi.yaml documents routes that do not exist: %s", strings.Join(stale, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+// templatePath turns gin's /games/:id and /media/*key into OpenAPI's
+// /games/{id} and /media/{key}.
+func templatePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -11,13 +11,14 @@ type OrderHandler struct {
     Usecase domain.OrderUsecase 
 }
 
-func NewOrderHandler(r *gin.Engine, us domain.OrderUsecase, jwtSecret string) {
+func NewOrderHandler(r gin.IRouter, us domain.OrderUsecase, jwtSecret string) {
     handler := &OrderHandler{Usecase: us} 
 
     protected := r.Group("/orders")
     protected.Use(middleware.AuthMiddleware(jwtSecret))
     {
         protected.POST("/buy", middleware.RoleBlock("customer"), handler.Purchase)
+        protected.POST("/buy-bundle", middleware.RoleBlock("customer"), handler.PurchaseBundle)
 		protected.POST("/topup", middleware.RoleBlock("customer"), handler.TopUp)
         
         protected.GET("/sales-report", middleware.RoleBlock("publisher"), handler.GetSalesReport)
@@ -28,26 +29,43 @@ func NewOrderHandler(r *gin.Engine, us domain.OrderUsecase, jwtSecret string) {
 func (h *OrderHandler) Purchase(c *gin.Context) {
     var req domain.PurchaseRequest
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(domain.ErrInvalidBody.Wrap(err))
         return
     }
 
     userID := c.MustGet("user_id").(int)
     err := h.Usecase.BuyGame(c.Request.Context(), userID, req.GameID) 
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
     c.JSON(http.StatusOK, gin.H{"message": "Purchase successful!"})
 }
 
+func (h *OrderHandler) PurchaseBundle(c *gin.Context) {
+    var req domain.BundlePurchaseRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.Error(domain.ErrInvalidBody.Wrap(err))
+        return
+    }
+
+    userID := c.MustGet("user_id").(int)
+    quote, err := h.Usecase.BuyBundle(c.Request.Context(), userID, req.BundleID)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Purchase successful!", "charged": quote})
+}
+
 func (h *OrderHandler) GetSalesReport(c *gin.Context) {
     publisherID := c.MustGet("user_id").(int)
 
     report, err := h.Usecase.GetPublisherSalesReport(c.Request.Context(), publisherID)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate report: " + err.Error()})
+        c.Error(err)
         return
     }
 
@@ -64,7 +82,7 @@ func (h *OrderHandler) TopUp(c *gin.Context) {
     }
 
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amount"})
+        c.Error(domain.ErrInvalidBody.Wrap(err))
         return
     }
 
@@ -72,7 +90,7 @@ func (h *OrderHandler) TopUp(c *gin.Context) {
     
     err := h.Usecase.AddBalance(c.Request.Context(), userID, req.Amount)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
@@ -84,7 +102,7 @@ func (h *OrderHandler) GetLibrary(c *gin.Context) {
 
     games, err := h.Usecase.GetCustomerLibrary(c.Request.Context(), userID)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch library"})
+        c.Error(err)
         return
     }
 
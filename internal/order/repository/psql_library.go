@@ -25,11 +25,12 @@ func (r *psqlLibraryRepository) AddToLibrary(ctx context.Context, userID int, ga
 
 func (r *psqlLibraryRepository) GetOwnedGames(ctx context.Context, userID int) ([]domain.Game, error) {
 	query := `
-		SELECT g.id, g.publisher_id, g.developer_id, g.game_name, g.price, g.stock_level
+		SELECT g.id, g.publisher_id, g.parent_game_id, g.developer_id, g.game_name, g.price, g.stock_level, g.deleted_at
 		FROM games g
 		INNER JOIN customer_game_library cgl ON g.id = cgl.game_id
 		INNER JOIN customers c ON cgl.customer_id = c.id
-		WHERE c.user_id = $1`
+		WHERE c.user_id = $1
+		ORDER BY cgl.purchase_date DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -40,7 +41,7 @@ func (r *psqlLibraryRepository) GetOwnedGames(ctx context.Context, userID int) (
 	var games []domain.Game
 	for rows.Next() {
 		var g domain.Game
-		err := rows.Scan(&g.ID, &g.PublisherID, &g.DeveloperID, &g.Name, &g.Price, &g.StockLevel)
+		err := rows.Scan(&g.ID, &g.PublisherID, &g.ParentGameID, &g.DeveloperID, &g.Name, &g.Price, &g.StockLevel, &g.DeletedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"cool-games/internal/domain"
 	"database/sql"
-	"errors"
+
+	"github.com/lib/pq"
 )
 
 type psqlOrderRepository struct {
@@ -17,13 +18,13 @@ func NewPsqlOrderRepository(db *sql.DB) *psqlOrderRepository {
 
 func (r *psqlOrderRepository) GetPublisherSales(ctx context.Context, publisherID int) ([]domain.SalesReportEntry, error) {
 	query := `
-        SELECT g.id, g.game_name, g.price, cgl.purchase_date, u.email -- REMOVED the 'd'
-        FROM customer_game_library cgl
-        JOIN games g ON cgl.game_id = g.id
-        JOIN customers c ON cgl.customer_id = c.id
+        SELECT g.id, g.game_name, o.total_amount, COALESCE(o.currency, ''), COALESCE(o.base_amount, o.total_amount), o.order_date, u.email
+        FROM orders o
+        JOIN games g ON o.game_id = g.id
+        JOIN customers c ON o.customer_id = c.id
         JOIN users u ON c.user_id = u.id
         WHERE g.publisher_id = $1
-        ORDER BY cgl.purchase_date DESC`
+        ORDER BY o.order_date DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, publisherID)
 	if err != nil {
@@ -34,7 +35,7 @@ func (r *psqlOrderRepository) GetPublisherSales(ctx context.Context, publisherID
 	var report []domain.SalesReportEntry
 	for rows.Next() {
 		var e domain.SalesReportEntry
-		if err := rows.Scan(&e.GameID, &e.GameName, &e.PriceAtSale, &e.PurchasedDate, &e.CustomerEmail); err != nil {
+		if err := rows.Scan(&e.GameID, &e.GameName, &e.PriceAtSale, &e.Currency, &e.BaseAmount, &e.PurchasedDate, &e.CustomerEmail); err != nil {
 			return nil, err
 		}
 		report = append(report, e)
@@ -42,60 +43,167 @@ func (r *psqlOrderRepository) GetPublisherSales(ctx context.Context, publisherID
 	return report, nil
 }
 
-func (r *psqlOrderRepository) ExecutePurchase(ctx context.Context, userID int, gameID int, price float64) error {
+// ExecutePurchase charges price.Amount to the wallet; the order keeps the
+// charged currency and the base-currency equivalent for reporting. It returns
+// the stock level the purchase left.
+func (r *psqlOrderRepository) ExecutePurchase(ctx context.Context, userID int, gameID int, price domain.Price) (int, error) {
     tx, err := r.db.BeginTx(ctx, nil)
-    if err != nil { return err }
+    if err != nil { return 0, err }
     defer tx.Rollback()
 
     var customerID int
     err = tx.QueryRowContext(ctx, "SELECT id FROM customers WHERE user_id = $1", userID).Scan(&customerID)
-    if err != nil { return errors.New("customer profile not found") }
+    if err == sql.ErrNoRows { return 0, domain.ErrCustomerNotFound }
+    if err != nil { return 0, err }
 
     res, err := tx.ExecContext(ctx, 
         "UPDATE customers SET current_balance = current_balance - $1 WHERE id = $2 AND current_balance >= $1", 
-        price, customerID)
-    if err != nil { return err }
-    if rows, _ := res.RowsAffected(); rows == 0 { return errors.New("insufficient balance") }
+        price.Amount, customerID)
+    if err != nil { return 0, err }
+    if rows, _ := res.RowsAffected(); rows == 0 { return 0, domain.ErrInsufficientBalance }
 
-    res, err = tx.ExecContext(ctx, 
-        "UPDATE games SET stock_level = stock_level - 1 WHERE id = $1 AND stock_level > 0", 
-        gameID)
-    if err != nil { return err }
-    if rows, _ := res.RowsAffected(); rows == 0 { return errors.New("game out of stock") }
+    var stockLevel int
+    err = tx.QueryRowContext(ctx, 
+        "UPDATE games SET stock_level = stock_level - 1 WHERE id = $1 AND stock_level > 0 AND deleted_at IS NULL RETURNING stock_level", 
+        gameID).Scan(&stockLevel)
+    if err == sql.ErrNoRows { return 0, domain.ErrOutOfStock.WithDetails(map[string]interface{}{"game_id": gameID}) }
+    if err != nil { return 0, err }
 
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO game_quantity_history (game_id, change_amount, transaction_date) 
-        VALUES ($1, -1, NOW())`, gameID)
-    if err != nil { return err }
+        INSERT INTO game_quantity_history (game_id, change_amount, reason, actor_user_id, transaction_date) 
+        VALUES ($1, -1, 'purchase', $2, NOW())`, gameID, userID)
+    if err != nil { return 0, err }
 
     var orderID int
     queryOrder := `
-        INSERT INTO orders (customer_id, game_id, qty, total_amount, order_date) 
-        VALUES ($1, $2, 1, $3, NOW()) RETURNING id`
+        INSERT INTO orders (customer_id, game_id, qty, total_amount, currency, base_amount, order_date) 
+        VALUES ($1, $2, 1, $3, $4, $5, NOW()) RETURNING id`
     
-    err = tx.QueryRowContext(ctx, queryOrder, customerID, gameID, price).Scan(&orderID)
-    if err != nil { return err }
+    err = tx.QueryRowContext(ctx, queryOrder, customerID, gameID, price.Amount, price.Currency, price.BaseAmount).Scan(&orderID)
+    if err != nil { return 0, err }
 
     _, err = tx.ExecContext(ctx, `
         INSERT INTO customer_game_library (customer_id, game_id, purchase_date) 
         VALUES ($1, $2, NOW()) 
         ON CONFLICT (customer_id, game_id) DO NOTHING`, customerID, gameID)
-    if err != nil { return err }
+    if err != nil { return 0, err }
 
     queryLedger := `
-        INSERT INTO ledger (customer_id, order_id, amount, type, transaction_date) 
-        VALUES ($1, $2, $3, 'debit', NOW())`
+        INSERT INTO ledger (customer_id, order_id, amount, currency, type, transaction_date) 
+        VALUES ($1, $2, $3, $4, 'debit', NOW())`
     
-    _, err = tx.ExecContext(ctx, queryLedger, customerID, orderID, price)
-    if err != nil { return err }
+    _, err = tx.ExecContext(ctx, queryLedger, customerID, orderID, price.Amount, price.Currency)
+    if err != nil { return 0, err }
+
+    if err := tx.Commit(); err != nil { return 0, err }
+    return stockLevel, nil
+}
+
+// ExecuteBundlePurchase buys every line of a bundle in one transaction: one
+// balance debit for the total, then per game the same stock, order, library
+// and ledger rows as a single purchase, with the orders pointing at the bundle.
+// It returns the stock level the purchase left for each game.
+func (r *psqlOrderRepository) ExecuteBundlePurchase(ctx context.Context, userID int, bundleID int, currency string, lines []domain.BundlePurchaseLine) (map[int]int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var customerID int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM customers WHERE user_id = $1 FOR UPDATE", userID).Scan(&customerID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gameIDs := make([]int64, len(lines))
+	var total float64
+	for i, l := range lines {
+		gameIDs[i] = int64(l.GameID)
+		total += l.Price
+	}
 
-    return tx.Commit()
+	// The quote was computed outside the transaction; if a game landed in the
+	// library since then the price no longer holds.
+	var alreadyOwned int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM customer_game_library WHERE customer_id = $1 AND game_id = ANY($2)`,
+		customerID, pq.Array(gameIDs)).Scan(&alreadyOwned)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyOwned > 0 {
+		return nil, domain.ErrBundleLibraryStale
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE customers SET current_balance = current_balance - $1 WHERE id = $2 AND current_balance >= $1",
+		total, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	stockLevels := make(map[int]int, len(lines))
+	for _, l := range lines {
+		var stockLevel int
+		err = tx.QueryRowContext(ctx,
+			"UPDATE games SET stock_level = stock_level - 1 WHERE id = $1 AND stock_level > 0 AND deleted_at IS NULL RETURNING stock_level",
+			l.GameID).Scan(&stockLevel)
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOutOfStock.WithDetails(map[string]interface{}{"game_id": l.GameID})
+		}
+		if err != nil {
+			return nil, err
+		}
+		stockLevels[l.GameID] = stockLevel
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO game_quantity_history (game_id, change_amount, reason, actor_user_id, transaction_date)
+			VALUES ($1, -1, 'purchase', $2, NOW())`, l.GameID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		var orderID int
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO orders (customer_id, game_id, bundle_id, qty, total_amount, currency, base_amount, order_date)
+			VALUES ($1, $2, $3, 1, $4, $5, $6, NOW()) RETURNING id`,
+			customerID, l.GameID, bundleID, l.Price, currency, l.BaseAmount).Scan(&orderID)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO customer_game_library (customer_id, game_id, purchase_date)
+			VALUES ($1, $2, NOW())`, customerID, l.GameID)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO ledger (customer_id, order_id, amount, currency, type, transaction_date)
+			VALUES ($1, $2, $3, $4, 'debit', NOW())`, customerID, orderID, l.Price, currency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return stockLevels, nil
 }
 
-func (r *psqlOrderRepository) RecordLedger(ctx context.Context, userID int, amount float64, description string) error {
+func (r *psqlOrderRepository) RecordLedger(ctx context.Context, userID int, amount float64, currency string, description string) error {
     query := `
-        INSERT INTO ledger (customer_id, amount, type, transaction_date) 
-        SELECT id, $1, 'credit', NOW() FROM customers WHERE user_id = $2`
-    _, err := r.db.ExecContext(ctx, query, amount, userID)
+        INSERT INTO ledger (customer_id, amount, currency, description, type, transaction_date) 
+        SELECT id, $1, $3, $4, 'credit', NOW() FROM customers WHERE user_id = $2`
+    _, err := r.db.ExecContext(ctx, query, amount, userID, currency, description)
     return err
 }
\ No newline at end of file
@@ -3,15 +3,27 @@ package usecase
 import (
 	"context"
 	"cool-games/internal/domain"
-	"errors"
+	"fmt"
+	"log/slog"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+var tracer = otel.Tracer("cool-games/internal/order/usecase")
+
 type orderUsecase struct {
 	gameRepo     domain.GameRepository
 	customerRepo domain.CustomerRepository
 	orderRepo domain.OrderRepository
 	libraryRepo  domain.LibraryRepository
+	bundleRepo   domain.BundleRepository
+	notifier     domain.Notifier
+	stockMonitor domain.StockMonitor
+	prices       domain.PriceResolver
+	metrics      domain.BusinessMetrics
 	timeout      time.Duration
 }
 
@@ -21,6 +33,11 @@ func NewOrderUsecase(
     c domain.CustomerRepository, 
     o domain.OrderRepository, 
     l domain.LibraryRepository,
+    b domain.BundleRepository,
+    n domain.Notifier,
+    sm domain.StockMonitor,
+    p domain.PriceResolver,
+    m domain.BusinessMetrics,
     t time.Duration,
 ) domain.OrderUsecase {
     return &orderUsecase{
@@ -28,59 +45,256 @@ func NewOrderUsecase(
         customerRepo: c,
         orderRepo:    o,
         libraryRepo:  l,
+        bundleRepo:   b,
+        notifier:     n,
+        stockMonitor: sm,
+        prices:       p,
+        metrics:      m,
         timeout:      t,
     }
 }
 
-func (u *orderUsecase) BuyGame(ctx context.Context, customerID int, gameID int) error {
+func (u *orderUsecase) BuyGame(ctx context.Context, customerID int, gameID int) (err error) {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.BuyGame")
+	defer span.End()
+	span.SetAttributes(attribute.Int("customer.id", customerID), attribute.Int("game.id", gameID))
 	c, cancel := context.WithTimeout(ctx, u.timeout)
 	defer cancel()
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			u.metrics.PurchaseFailed(domain.PurchaseKindGame, err)
+		}
+	}()
 
 	game, err := u.gameRepo.GetByID(c, gameID)
 	if err != nil { return err }
-	if game.StockLevel <= 0 { return errors.New("insufficient stock") }
+	if game.StockLevel <= 0 { return domain.ErrOutOfStock.WithDetails(map[string]interface{}{"game_id": gameID}) }
+
+	price, err := u.prices.PriceFor(c, gameID, game.Price, customerID)
+	if err != nil { return err }
 
 	customer, err := u.customerRepo.GetByUserID(c, customerID)
 	if err != nil { return err }
-	if customer.CurrentBalance < game.Price { return errors.New("insufficient balance") }
+	if customer.CurrentBalance < price.Amount { return domain.ErrInsufficientBalance }
 
 	if u.libraryRepo != nil {
-		ownedGames, _ := u.libraryRepo.GetOwnedGames(c, customerID)
+		ownedGames, err := u.libraryRepo.GetOwnedGames(c, customerID)
+		if err != nil { return err }
+		ownsBase := false
 		for _, g := range ownedGames {
 			if g.ID == gameID {
-				return errors.New("you already own this game")
+				return domain.ErrAlreadyOwned
+			}
+			if game.ParentGameID != nil && g.ID == *game.ParentGameID {
+				ownsBase = true
 			}
 		}
+		if game.ParentGameID != nil && !ownsBase {
+			return domain.ErrBaseGameNotOwned
+		}
 	}
 
-	return u.orderRepo.ExecutePurchase(c, customerID, gameID, game.Price)
+	stockLevel, err := u.orderRepo.ExecutePurchase(c, customerID, gameID, price)
+	if err != nil {
+		return err
+	}
+	u.metrics.PurchaseCompleted(domain.PurchaseKindGame, price.Amount, price.Currency)
+
+	if u.stockMonitor != nil {
+		u.stockMonitor.StockChanged(c, gameID, stockLevel+1, stockLevel)
+	}
+
+	u.notify(c, domain.Notification{
+		UserID:  customerID,
+		Type:    domain.NotificationPurchaseCompleted,
+		Title:   "Purchase completed",
+		Message: fmt.Sprintf("%s was added to your library for %.2f %s", game.Name, price.Amount, price.Currency),
+	})
+	return nil
 }
 
+// BuyBundle buys the games of a bundle the customer does not own yet, at the
+// "complete the bundle" price, and returns the quote that was charged.
+func (u *orderUsecase) BuyBundle(ctx context.Context, customerID int, bundleID int) (_ domain.BundleQuote, err error) {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.BuyBundle")
+	defer span.End()
+	span.SetAttributes(attribute.Int("customer.id", customerID), attribute.Int("bundle.id", bundleID))
+	c, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			u.metrics.PurchaseFailed(domain.PurchaseKindBundle, err)
+		}
+	}()
+
+	bundle, err := u.bundleRepo.GetByID(c, bundleID)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+
+	ownedGames, err := u.libraryRepo.GetOwnedGames(c, customerID)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+	owned := map[int]bool{}
+	for _, g := range ownedGames {
+		owned[g.ID] = true
+	}
+	inBundle := map[int]bool{}
+	for _, g := range bundle.Games {
+		inBundle[g.ID] = true
+	}
+
+	quote := bundle.Quote(owned)
+	if quote.Price == 0 {
+		return domain.BundleQuote{}, domain.ErrBundleFullyOwned
+	}
+	quote, err = u.prices.ConvertQuote(c, quote, customerID)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+
+	var lines []domain.BundlePurchaseLine
+	for i, g := range bundle.Games {
+		if owned[g.ID] {
+			continue
+		}
+		if g.DeletedAt != nil {
+			return domain.BundleQuote{}, domain.ErrBundleUnavailable
+		}
+		if g.StockLevel <= 0 {
+			return domain.BundleQuote{}, domain.ErrOutOfStock.WithDetails(map[string]interface{}{"game_id": g.ID, "game_name": g.Name})
+		}
+		if g.ParentGameID != nil && !owned[*g.ParentGameID] && !inBundle[*g.ParentGameID] {
+			return domain.BundleQuote{}, domain.ErrBaseGameNotOwned
+		}
+		lines = append(lines, domain.BundlePurchaseLine{
+			GameID:     g.ID,
+			Price:      quote.Items[i].Price,
+			BaseAmount: quote.Items[i].BaseAmount,
+		})
+	}
+
+	customer, err := u.customerRepo.GetByUserID(c, customerID)
+	if err != nil { return domain.BundleQuote{}, err }
+	if customer.CurrentBalance < quote.Price { return domain.BundleQuote{}, domain.ErrInsufficientBalance }
+
+	stockLevels, err := u.orderRepo.ExecuteBundlePurchase(c, customerID, bundleID, quote.Currency, lines)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+	u.metrics.PurchaseCompleted(domain.PurchaseKindBundle, quote.Price, quote.Currency)
+
+	if u.stockMonitor != nil {
+		for _, l := range lines {
+			u.stockMonitor.StockChanged(c, l.GameID, stockLevels[l.GameID]+1, stockLevels[l.GameID])
+		}
+	}
+
+	u.notify(c, domain.Notification{
+		UserID:  customerID,
+		Type:    domain.NotificationPurchaseCompleted,
+		Title:   "Purchase completed",
+		Message: fmt.Sprintf("%d games from %s were added to your library for %.2f %s", len(lines), bundle.Name, quote.Price, quote.Currency),
+	})
+	return quote, nil
+}
+
+// notify is best effort: a purchase that went through must not be reported
+// as failed because the notification could not be delivered.
+func (u *orderUsecase) notify(ctx context.Context, n domain.Notification) {
+	if u.notifier == nil {
+		return
+	}
+	if err := u.notifier.Notify(ctx, n); err != nil {
+		slog.WarnContext(ctx, "order: failed to notify user", "user_id", n.UserID, "err", err)
+	}
+}
+
+// AddBalance tops up the wallet; amount is in the customer's wallet currency.
 func (u *orderUsecase) AddBalance(ctx context.Context, userID int, amount float64) error {
+    ctx, span := tracer.Start(ctx, "OrderUsecase.AddBalance")
+    defer span.End()
     c, cancel := context.WithTimeout(ctx, u.timeout)
     defer cancel()
 
-    err := u.customerRepo.UpdateBalance(c, userID, amount)
+    customer, err := u.customerRepo.GetByUserID(c, userID)
+    if err != nil { return err }
+    currency := customer.Currency
+    if currency == "" {
+        currency = u.prices.BaseCurrency()
+    }
+
+    err = u.customerRepo.UpdateBalance(c, userID, amount)
     if err != nil { return err }
 
-    return u.orderRepo.RecordLedger(c, userID, amount, "Top-up")
+    if err := u.orderRepo.RecordLedger(c, userID, amount, currency, "Top-up"); err != nil {
+        return err
+    }
+    u.metrics.BalanceToppedUp(amount, currency)
+    return nil
 }
 
 func (u *orderUsecase) GetPublisherSalesReport(ctx context.Context, userID int) ([]domain.SalesReportEntry, error) {
+    ctx, span := tracer.Start(ctx, "OrderUsecase.GetPublisherSalesReport")
+    defer span.End()
     c, cancel := context.WithTimeout(ctx, u.timeout)
     defer cancel()
 
     pubID, err := u.gameRepo.GetPublisherIDByUserID(c, userID)
     if err != nil {
-        return nil, errors.New("publisher profile not found")
+        return nil, err
     }
 
     return u.orderRepo.GetPublisherSales(c, pubID)
 }
 
 func (u *orderUsecase) GetCustomerLibrary(ctx context.Context, userID int) ([]domain.Game, error) {
+    ctx, span := tracer.Start(ctx, "OrderUsecase.GetCustomerLibrary")
+    defer span.End()
     c, cancel := context.WithTimeout(ctx, u.timeout)
     defer cancel()
 
-    return u.libraryRepo.GetOwnedGames(c, userID)
+    owned, err := u.libraryRepo.GetOwnedGames(c, userID)
+    if err != nil {
+        return nil, err
+    }
+    return groupDLC(owned), nil
+}
+
+// groupDLC nests owned DLC under their base game. DLC whose base game is not
+// in the library stays at the top level so nothing the customer paid for
+// disappears from the list.
+func groupDLC(games []domain.Game) []domain.Game {
+	baseIndex := map[int]int{}
+	var res []domain.Game
+	for _, g := range games {
+		if g.ParentGameID == nil {
+			baseIndex[g.ID] = len(res)
+			res = append(res, g)
+		}
+	}
+
+	for _, g := range games {
+		if g.ParentGameID == nil {
+			continue
+		}
+		i, ok := baseIndex[*g.ParentGameID]
+		if !ok {
+			res = append(res, g)
+			continue
+		}
+		res[i].DLC = append(res[i].DLC, domain.DLC{
+			ID:         g.ID,
+			Name:       g.Name,
+			Price:      g.Price,
+			StockLevel: g.StockLevel,
+			Owned:      true,
+		})
+	}
+	return res
 }
\ No newline at end of file
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"cool-games/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestGroupDLC(t *testing.T) {
+	base := func(id int) domain.Game {
+		return domain.Game{ID: id, Name: "base", Price: 20, StockLevel: 1}
+	}
+	dlc := func(id, parent int) domain.Game {
+		return domain.Game{ID: id, Name: "dlc", Price: 5, StockLevel: 2, ParentGameID: &parent}
+	}
+	owned := func(id int) domain.DLC {
+		return domain.DLC{ID: id, Name: "dlc", Price: 5, StockLevel: 2, Owned: true}
+	}
+	withDLC := func(g domain.Game, dlc ...domain.DLC) domain.Game {
+		g.DLC = dlc
+		return g
+	}
+
+	tests := []struct {
+		name  string
+		games []domain.Game
+		want  []domain.Game
+	}{
+		{
+			name: "empty library",
+		},
+		{
+			name:  "no DLC",
+			games: []domain.Game{base(1), base(2)},
+			want:  []domain.Game{base(1), base(2)},
+		},
+		{
+			name:  "DLC nests under its base game, whatever the order",
+			games: []domain.Game{dlc(10, 1), base(1), base(2), dlc(11, 1), dlc(20, 2)},
+			want: []domain.Game{
+				withDLC(base(1), owned(10), owned(11)),
+				withDLC(base(2), owned(20)),
+			},
+		},
+		{
+			name:  "DLC without its base game stays top level",
+			games: []domain.Game{base(1), dlc(30, 3)},
+			want:  []domain.Game{base(1), dlc(30, 3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupDLC(tt.games); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
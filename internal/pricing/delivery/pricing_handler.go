@@ -0,0 +1,111 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PricingHandler struct {
+	Usecase domain.PricingUsecase
+}
+
+func NewPricingHandler(r gin.IRouter, us domain.PricingUsecase, jwtSecret string) {
+	handler := &PricingHandler{Usecase: us}
+
+	r.GET("/exchange-rates", handler.FetchRates)
+	r.GET("/games/:id/regional-prices", handler.FetchRegionalPrices)
+
+	adminOnly := r.Group("/exchange-rates")
+	adminOnly.Use(middleware.AuthMiddleware(jwtSecret))
+	adminOnly.Use(middleware.RoleBlock("admin"))
+	{
+		adminOnly.PUT("/:currency", handler.SetRate)
+		adminOnly.DELETE("/:currency", handler.DeleteRate)
+	}
+
+	protected := r.Group("/games/:id/regional-prices")
+	protected.Use(middleware.AuthMiddleware(jwtSecret))
+	{
+		protected.PUT("/:region", middleware.RoleBlock("publisher"), handler.SetRegionalPrice)
+		protected.DELETE("/:region", middleware.RoleBlock("publisher"), handler.DeleteRegionalPrice)
+	}
+}
+
+func (h *PricingHandler) FetchRates(c *gin.Context) {
+	res, err := h.Usecase.GetRates(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"base_currency": h.Usecase.BaseCurrency(), "rates": res})
+}
+
+func (h *PricingHandler) SetRate(c *gin.Context) {
+	var rate domain.ExchangeRate
+	if err := c.ShouldBindJSON(&rate); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+	rate.Currency = c.Param("currency")
+
+	userID := c.MustGet("user_id").(int)
+	if err := h.Usecase.SetRate(c.Request.Context(), &rate, userID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+func (h *PricingHandler) DeleteRate(c *gin.Context) {
+	if err := h.Usecase.DeleteRate(c.Request.Context(), c.Param("currency")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PricingHandler) FetchRegionalPrices(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+
+	res, err := h.Usecase.GetRegionalPrices(c.Request.Context(), gameID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *PricingHandler) SetRegionalPrice(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+	var price domain.RegionalPrice
+	if err := c.ShouldBindJSON(&price); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+	price.GameID = gameID
+	price.Region = c.Param("region")
+
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+	if err := h.Usecase.SetRegionalPrice(c.Request.Context(), &price, userID, role); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, price)
+}
+
+func (h *PricingHandler) DeleteRegionalPrice(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	if err := h.Usecase.DeleteRegionalPrice(c.Request.Context(), gameID, c.Param("region"), userID, role); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
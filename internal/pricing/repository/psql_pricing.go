@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+)
+
+type psqlPricingRepository struct {
+	db *sql.DB
+}
+
+func NewPsqlPricingRepository(db *sql.DB) domain.PricingRepository {
+	return &psqlPricingRepository{db: db}
+}
+
+func (r *psqlPricingRepository) FetchRates(ctx context.Context) ([]domain.ExchangeRate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT currency, rate, updated_by, updated_at FROM exchange_rates ORDER BY currency`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []domain.ExchangeRate{}
+	for rows.Next() {
+		var rate domain.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedBy, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *psqlPricingRepository) GetRate(ctx context.Context, currency string) (domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	err := r.db.QueryRowContext(ctx, `SELECT currency, rate, updated_by, updated_at FROM exchange_rates WHERE currency = $1`, currency).
+		Scan(&rate.Currency, &rate.Rate, &rate.UpdatedBy, &rate.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return domain.ExchangeRate{}, domain.ErrExchangeRateNotFound
+	}
+	return rate, err
+}
+
+func (r *psqlPricingRepository) SaveRate(ctx context.Context, rate *domain.ExchangeRate) error {
+	query := `
+		INSERT INTO exchange_rates (currency, rate, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (currency) DO UPDATE
+		SET rate = EXCLUDED.rate, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at`
+	return r.db.QueryRowContext(ctx, query, rate.Currency, rate.Rate, rate.UpdatedBy).Scan(&rate.UpdatedAt)
+}
+
+// DeleteRate keeps rates that wallets or regional prices are denominated in,
+// since those could no longer be converted.
+func (r *psqlPricingRepository) DeleteRate(ctx context.Context, currency string) error {
+	if _, err := r.GetRate(ctx, currency); err != nil {
+		return err
+	}
+
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM exchange_rates WHERE currency = $1
+		  AND NOT EXISTS (SELECT 1 FROM customers WHERE currency = $1)
+		  AND NOT EXISTS (SELECT 1 FROM game_regional_prices WHERE currency = $1)`, currency)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrExchangeRateInUse
+	}
+	return nil
+}
+
+func (r *psqlPricingRepository) FetchRegionalPrices(ctx context.Context, gameID int) ([]domain.RegionalPrice, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT game_id, region, currency, price FROM game_regional_prices
+		WHERE game_id = $1 ORDER BY region`, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []domain.RegionalPrice{}
+	for rows.Next() {
+		var p domain.RegionalPrice
+		if err := rows.Scan(&p.GameID, &p.Region, &p.Currency, &p.Price); err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+func (r *psqlPricingRepository) GetRegionalPrice(ctx context.Context, gameID int, region string) (domain.RegionalPrice, error) {
+	var p domain.RegionalPrice
+	err := r.db.QueryRowContext(ctx, `
+		SELECT game_id, region, currency, price FROM game_regional_prices
+		WHERE game_id = $1 AND region = $2`, gameID, region).Scan(&p.GameID, &p.Region, &p.Currency, &p.Price)
+	if err == sql.ErrNoRows {
+		return domain.RegionalPrice{}, domain.ErrRegionalPriceNotFound
+	}
+	return p, err
+}
+
+func (r *psqlPricingRepository) SaveRegionalPrice(ctx context.Context, p *domain.RegionalPrice) error {
+	query := `
+		INSERT INTO game_regional_prices (game_id, region, currency, price)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (game_id, region) DO UPDATE
+		SET currency = EXCLUDED.currency, price = EXCLUDED.price, updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, p.GameID, p.Region, p.Currency, p.Price)
+	return err
+}
+
+func (r *psqlPricingRepository) DeleteRegionalPrice(ctx context.Context, gameID int, region string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM game_regional_prices WHERE game_id = $1 AND region = $2`, gameID, region)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrRegionalPriceNotFound
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("cool-games/internal/pricing/usecase")
+
+var (
+	currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+	regionCode   = regexp.MustCompile(`^[A-Z]{2,3}$`)
+)
+
+type pricingUsecase struct {
+	pricingRepo    domain.PricingRepository
+	gameRepo       domain.GameRepository
+	customerRepo   domain.CustomerRepository
+	baseCurrency   string
+	contextTimeout time.Duration
+}
+
+// NewPricingUsecase prices games for customers. Game prices and bundle prices
+// are in baseCurrency; regional prices may be in any currency with a rate.
+func NewPricingUsecase(p domain.PricingRepository, g domain.GameRepository, c domain.CustomerRepository, baseCurrency string, timeout time.Duration) domain.PricingUsecase {
+	return &pricingUsecase{
+		pricingRepo:    p,
+		gameRepo:       g,
+		customerRepo:   c,
+		baseCurrency:   strings.ToUpper(baseCurrency),
+		contextTimeout: timeout,
+	}
+}
+
+func (u *pricingUsecase) BaseCurrency() string {
+	return u.baseCurrency
+}
+
+func (u *pricingUsecase) ValidateCurrency(ctx context.Context, currency string) error {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.ValidateCurrency")
+	defer span.End()
+	_, err := u.rate(ctx, currency)
+	return err
+}
+
+// rate returns how many units of currency one base unit buys.
+func (u *pricingUsecase) rate(ctx context.Context, currency string) (float64, error) {
+	if currency == u.baseCurrency {
+		return 1, nil
+	}
+	r, err := u.pricingRepo.GetRate(ctx, currency)
+	if err != nil {
+		return 0, err
+	}
+	return r.Rate, nil
+}
+
+// walletCurrency is the currency a user pays in; non-customers see base prices.
+// A failed lookup is returned rather than quietly pricing in the base currency.
+func (u *pricingUsecase) walletCurrency(ctx context.Context, userID int) (domain.Customer, string, error) {
+	if userID == 0 {
+		return domain.Customer{}, u.baseCurrency, nil
+	}
+	customer, err := u.customerRepo.GetByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrCustomerNotFound) {
+		return domain.Customer{}, u.baseCurrency, nil
+	}
+	if err != nil {
+		return domain.Customer{}, "", err
+	}
+	if customer.Currency == "" {
+		return customer, u.baseCurrency, nil
+	}
+	return customer, customer.Currency, nil
+}
+
+// PriceFor uses the game's price for the customer's region when there is one,
+// otherwise the base price, converted into the customer's wallet currency.
+func (u *pricingUsecase) PriceFor(ctx context.Context, gameID int, basePrice float64, userID int) (domain.Price, error) {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.PriceFor")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	customer, currency, err := u.walletCurrency(c, userID)
+	if err != nil {
+		return domain.Price{}, err
+	}
+
+	amount, amountCurrency := basePrice, u.baseCurrency
+	if customer.Region != "" {
+		rp, err := u.pricingRepo.GetRegionalPrice(c, gameID, customer.Region)
+		if err != nil && err != domain.ErrRegionalPriceNotFound {
+			return domain.Price{}, err
+		}
+		if err == nil {
+			amount, amountCurrency = rp.Price, rp.Currency
+		}
+	}
+
+	fromRate, err := u.rate(c, amountCurrency)
+	if err != nil {
+		return domain.Price{}, err
+	}
+	toRate, err := u.rate(c, currency)
+	if err != nil {
+		return domain.Price{}, err
+	}
+
+	baseAmount := amount / fromRate
+	price := domain.Price{Currency: currency, BaseAmount: domain.RoundCents(baseAmount)}
+	if amountCurrency == currency {
+		price.Amount = amount
+	} else {
+		price.Amount = domain.RoundCents(baseAmount * toRate)
+	}
+	return price, nil
+}
+
+// ConvertQuote expresses a base-currency bundle quote in the user's wallet
+// currency. Every item is converted on its own and the total is their sum,
+// so what is charged always matches the lines recorded.
+func (u *pricingUsecase) ConvertQuote(ctx context.Context, q domain.BundleQuote, userID int) (domain.BundleQuote, error) {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.ConvertQuote")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	_, currency, err := u.walletCurrency(c, userID)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+	rate, err := u.rate(c, currency)
+	if err != nil {
+		return domain.BundleQuote{}, err
+	}
+
+	q.Currency = currency
+	q.BundlePrice = domain.RoundCents(q.BundlePrice * rate)
+	q.Price = 0
+	items := make([]domain.BundleQuoteItem, len(q.Items))
+	for i, item := range q.Items {
+		item.Price = domain.RoundCents(item.BaseAmount * rate)
+		if !item.Owned {
+			q.Price += item.Price
+		}
+		items[i] = item
+	}
+	q.Items = items
+	q.Price = domain.RoundCents(q.Price)
+	return q, nil
+}
+
+func (u *pricingUsecase) GetRates(ctx context.Context) ([]domain.ExchangeRate, error) {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.GetRates")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.pricingRepo.FetchRates(c)
+}
+
+func (u *pricingUsecase) SetRate(ctx context.Context, rate *domain.ExchangeRate, actorID int) error {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.SetRate")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	rate.Currency = strings.ToUpper(rate.Currency)
+	if !currencyCode.MatchString(rate.Currency) {
+		return domain.ErrInvalidCurrency
+	}
+	if rate.Currency == u.baseCurrency {
+		return domain.ErrBaseCurrencyRate
+	}
+	rate.UpdatedBy = &actorID
+	return u.pricingRepo.SaveRate(c, rate)
+}
+
+func (u *pricingUsecase) DeleteRate(ctx context.Context, currency string) error {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.DeleteRate")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.pricingRepo.DeleteRate(c, strings.ToUpper(currency))
+}
+
+func (u *pricingUsecase) GetRegionalPrices(ctx context.Context, gameID int) ([]domain.RegionalPrice, error) {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.GetRegionalPrices")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.gameRepo.GetByID(c, gameID); err != nil {
+		return nil, err
+	}
+	return u.pricingRepo.FetchRegionalPrices(c, gameID)
+}
+
+func (u *pricingUsecase) SetRegionalPrice(ctx context.Context, p *domain.RegionalPrice, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.SetRegionalPrice")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if err := u.authorizeOwner(c, p.GameID, requesterID, role); err != nil {
+		return err
+	}
+
+	p.Region = strings.ToUpper(p.Region)
+	p.Currency = strings.ToUpper(p.Currency)
+	if !regionCode.MatchString(p.Region) {
+		return domain.ErrInvalidRegion
+	}
+	if err := u.ValidateCurrency(c, p.Currency); err != nil {
+		return err
+	}
+	return u.pricingRepo.SaveRegionalPrice(c, p)
+}
+
+func (u *pricingUsecase) DeleteRegionalPrice(ctx context.Context, gameID int, region string, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "PricingUsecase.DeleteRegionalPrice")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if err := u.authorizeOwner(c, gameID, requesterID, role); err != nil {
+		return err
+	}
+	return u.pricingRepo.DeleteRegionalPrice(c, gameID, strings.ToUpper(region))
+}
+
+func (u *pricingUsecase) authorizeOwner(ctx context.Context, gameID int, requesterID int, role string) error {
+	game, err := u.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if role == "admin" {
+		return nil
+	}
+	pubID, err := u.gameRepo.GetPublisherIDByUserID(ctx, requesterID)
+	if err != nil || game.PublisherID != pubID {
+		return domain.ErrUnauthorizedAction
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	Usecase domain.TagUsecase
+}
+
+func NewTagHandler(r gin.IRouter, us domain.TagUsecase, jwtSecret string) {
+	handler := &TagHandler{Usecase: us}
+
+	r.GET("/tags", handler.Fetch)
+	r.GET("/games/:id/tags", middleware.OptionalAuth(jwtSecret), handler.FetchForGame)
+
+	protected := r.Group("/games/:id/tags")
+	protected.Use(middleware.AuthMiddleware(jwtSecret))
+	{
+		protected.POST("", middleware.RoleBlock("customer", "publisher"), handler.Apply)
+		protected.DELETE("/:name", middleware.RoleBlock("customer", "publisher"), handler.Remove)
+	}
+}
+
+func (h *TagHandler) Fetch(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+	res, err := h.Usecase.GetPopular(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if res == nil {
+		res = []domain.Tag{}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *TagHandler) FetchForGame(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+
+	res, err := h.Usecase.GetForGame(c.Request.Context(), gameID, c.GetInt("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if res == nil {
+		res = []domain.GameTag{}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *TagHandler) Apply(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+	var req domain.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+	res, err := h.Usecase.Apply(c.Request.Context(), gameID, req.Name, userID, role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *TagHandler) Remove(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("user_id").(int)
+	role := c.MustGet("role").(string)
+
+	if err := h.Usecase.Remove(c.Request.Context(), gameID, c.Param("name"), userID, role); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+)
+
+type psqlTagRepository struct {
+	db *sql.DB
+}
+
+func NewPsqlTagRepository(db *sql.DB) domain.TagRepository {
+	return &psqlTagRepository{db: db}
+}
+
+// Fetch lists tags by how many live games carry them, optionally narrowed to
+// names starting with query.
+func (r *psqlTagRepository) Fetch(ctx context.Context, query string, limit int) ([]domain.Tag, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.tag_name, COUNT(DISTINCT gt.game_id)
+		FROM tags t
+		JOIN game_tags gt ON gt.tag_id = t.id
+		JOIN games g ON g.id = gt.game_id AND g.deleted_at IS NULL
+		WHERE t.tag_name LIKE $1 || '%'
+		GROUP BY t.id, t.tag_name
+		ORDER BY COUNT(DISTINCT gt.game_id) DESC, t.tag_name
+		LIMIT $2`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Tag
+	for rows.Next() {
+		var t domain.Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Count); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+func (r *psqlTagRepository) FetchForGame(ctx context.Context, gameID int, userID int) ([]domain.GameTag, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.tag_name, COUNT(*), COALESCE(BOOL_OR(gt.user_id = $2), false)
+		FROM game_tags gt
+		JOIN tags t ON t.id = gt.tag_id
+		WHERE gt.game_id = $1
+		GROUP BY t.id, t.tag_name
+		ORDER BY COUNT(*) DESC, t.tag_name`, gameID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.GameTag
+	for rows.Next() {
+		var t domain.GameTag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Count, &t.AppliedByMe); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+func (r *psqlTagRepository) CountByUser(ctx context.Context, gameID int, userID int) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM game_tags WHERE game_id = $1 AND user_id = $2`, gameID, userID).Scan(&n)
+	return n, err
+}
+
+// Apply creates the tag on first use. Applying a tag twice is a no-op.
+func (r *psqlTagRepository) Apply(ctx context.Context, gameID int, userID int, name string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var tagID int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO tags (tag_name) VALUES ($1)
+		ON CONFLICT (tag_name) DO UPDATE SET tag_name = EXCLUDED.tag_name
+		RETURNING id`, name).Scan(&tagID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO game_tags (game_id, tag_id, user_id) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, gameID, tagID, userID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *psqlTagRepository) Remove(ctx context.Context, gameID int, userID int, name string) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM game_tags gt USING tags t
+		WHERE gt.tag_id = t.id AND gt.game_id = $1 AND gt.user_id = $2 AND t.tag_name = $3`, gameID, userID, name)
+	return removed(res, err)
+}
+
+func (r *psqlTagRepository) RemoveAll(ctx context.Context, gameID int, name string) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM game_tags gt USING tags t
+		WHERE gt.tag_id = t.id AND gt.game_id = $1 AND t.tag_name = $2`, gameID, name)
+	return removed(res, err)
+}
+
+func removed(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrTagNotFound
+	}
+	return nil
+}
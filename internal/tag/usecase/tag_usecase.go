@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("cool-games/internal/tag/usecase")
+
+const (
+	defaultTagLimit = 50
+	maxTagLimit     = 200
+)
+
+type tagUsecase struct {
+	tagRepo        domain.TagRepository
+	gameRepo       domain.GameRepository
+	contextTimeout time.Duration
+}
+
+func NewTagUsecase(t domain.TagRepository, g domain.GameRepository, timeout time.Duration) domain.TagUsecase {
+	return &tagUsecase{
+		tagRepo:        t,
+		gameRepo:       g,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *tagUsecase) GetPopular(ctx context.Context, query string, limit int) ([]domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.GetPopular")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = defaultTagLimit
+	}
+	if limit > maxTagLimit {
+		limit = maxTagLimit
+	}
+	// An unusable prefix simply matches nothing rather than failing the search.
+	prefix, err := domain.NormalizeTag(query)
+	if err != nil && query != "" {
+		return []domain.Tag{}, nil
+	}
+	return u.tagRepo.Fetch(c, prefix, limit)
+}
+
+func (u *tagUsecase) GetForGame(ctx context.Context, gameID int, viewerUserID int) ([]domain.GameTag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.GetForGame")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.gameRepo.GetByID(c, gameID); err != nil {
+		return nil, err
+	}
+	return u.tagRepo.FetchForGame(c, gameID, viewerUserID)
+}
+
+// Apply tags a game for the requester. Customers can tag any game; publishers
+// only their own, so they cannot label a competitor's catalog.
+func (u *tagUsecase) Apply(ctx context.Context, gameID int, name string, requesterID int, role string) ([]domain.GameTag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.Apply")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	name, err := domain.NormalizeTag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := u.gameRepo.GetByID(c, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "publisher" {
+		pubID, err := u.gameRepo.GetPublisherIDByUserID(c, requesterID)
+		if err != nil {
+			return nil, err
+		}
+		if game.PublisherID != pubID {
+			return nil, domain.ErrUnauthorizedAction
+		}
+	}
+
+	count, err := u.tagRepo.CountByUser(c, gameID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxTagsPerUser {
+		return nil, domain.ErrTooManyTags
+	}
+
+	if err := u.tagRepo.Apply(c, gameID, requesterID, name); err != nil {
+		return nil, err
+	}
+	return u.tagRepo.FetchForGame(c, gameID, requesterID)
+}
+
+// Remove takes back the requester's own tag. Admins remove the tag from the
+// game for everyone.
+func (u *tagUsecase) Remove(ctx context.Context, gameID int, name string, requesterID int, role string) error {
+	ctx, span := tracer.Start(ctx, "TagUsecase.Remove")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	name, err := domain.NormalizeTag(name)
+	if err != nil {
+		return domain.ErrTagNotFound
+	}
+	if role == "admin" {
+		return u.tagRepo.RemoveAll(c, gameID, name)
+	}
+	return u.tagRepo.Remove(c, gameID, requesterID, name)
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"errors"
+	"testing"
+	"time"
+)
+
+type publisherRepo struct {
+	domain.GameRepository
+	publisherID int
+	err         error
+}
+
+func (r publisherRepo) GetByID(ctx context.Context, id int) (domain.Game, error) {
+	return domain.Game{ID: id, PublisherID: 1}, nil
+}
+
+func (r publisherRepo) GetPublisherIDByUserID(ctx context.Context, userID int) (int, error) {
+	return r.publisherID, r.err
+}
+
+type memTagRepo struct {
+	domain.TagRepository
+}
+
+func (memTagRepo) CountByUser(ctx context.Context, gameID int, userID int) (int, error) {
+	return 0, nil
+}
+
+func (memTagRepo) Apply(ctx context.Context, gameID int, userID int, name string) error {
+	return nil
+}
+
+func (memTagRepo) FetchForGame(ctx context.Context, gameID int, userID int) ([]domain.GameTag, error) {
+	return nil, nil
+}
+
+func TestApplyPublisherAccess(t *testing.T) {
+	dbDown := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		games   publisherRepo
+		wantErr error
+	}{
+		{name: "own game", games: publisherRepo{publisherID: 1}},
+		{name: "another publisher's game", games: publisherRepo{publisherID: 2}, wantErr: domain.ErrUnauthorizedAction},
+		{name: "no publisher profile", games: publisherRepo{err: domain.ErrPublisherNotFound}, wantErr: domain.ErrPublisherNotFound},
+		{name: "lookup fails", games: publisherRepo{err: dbDown}, wantErr: dbDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewTagUsecase(memTagRepo{}, tt.games, time.Minute)
+			_, err := u.Apply(context.Background(), 5, "co-op", 10, "publisher")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
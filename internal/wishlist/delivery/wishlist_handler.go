@@ -0,0 +1,71 @@
+package delivery
+
+import (
+	"cool-games/internal/domain"
+	"cool-games/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WishlistHandler struct {
+	Usecase domain.WishlistUsecase
+}
+
+func NewWishlistHandler(r gin.IRouter, us domain.WishlistUsecase, jwtSecret string) {
+	handler := &WishlistHandler{Usecase: us}
+
+	wishlist := r.Group("/me/wishlist")
+	wishlist.Use(middleware.AuthMiddleware(jwtSecret))
+	wishlist.Use(middleware.RoleBlock("customer"))
+	{
+		wishlist.GET("", handler.Fetch)
+		wishlist.POST("", handler.Add)
+		wishlist.DELETE("/:gameId", handler.Remove)
+	}
+}
+
+func (h *WishlistHandler) Fetch(c *gin.Context) {
+	userID := c.MustGet("user_id").(int)
+
+	items, err := h.Usecase.GetWishlist(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if items == nil {
+		items = []domain.WishlistItem{}
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *WishlistHandler) Add(c *gin.Context) {
+	var req domain.WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.ErrInvalidBody.Wrap(err))
+		return
+	}
+
+	userID := c.MustGet("user_id").(int)
+	if err := h.Usecase.Add(c.Request.Context(), userID, req.GameID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Game added to wishlist"})
+}
+
+func (h *WishlistHandler) Remove(c *gin.Context) {
+	gameID, _ := strconv.Atoi(c.Param("gameId"))
+	userID := c.MustGet("user_id").(int)
+
+	if err := h.Usecase.Remove(c.Request.Context(), userID, gameID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"database/sql"
+)
+
+type psqlWishlistRepository struct {
+	db *sql.DB
+}
+
+func NewPsqlWishlistRepository(db *sql.DB) domain.WishlistRepository {
+	return &psqlWishlistRepository{db: db}
+}
+
+// Add wishlists the game with price, what the customer pays for it now, as
+// the first snapshot. Adding a game that is already wishlisted is a no-op.
+func (r *psqlWishlistRepository) Add(ctx context.Context, userID int, gameID int, price domain.Price) error {
+	query := `
+		INSERT INTO wishlists (customer_id, game_id, last_seen_price, last_seen_currency, last_seen_stock)
+		SELECT c.id, g.id, $3, $4, g.stock_level
+		FROM customers c, games g
+		WHERE c.user_id = $1 AND g.id = $2
+		ON CONFLICT (customer_id, game_id) DO NOTHING`
+
+	res, err := r.db.ExecContext(ctx, query, userID, gameID, price.Amount, price.Currency)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows > 0 {
+		return nil
+	}
+
+	// Nothing was inserted: either the game is already on the wishlist or
+	// there is no customer profile to attach it to.
+	var exists bool
+	err = r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM customers WHERE user_id = $1)", userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrCustomerNotFound
+	}
+	return nil
+}
+
+func (r *psqlWishlistRepository) Remove(ctx context.Context, userID int, gameID int) error {
+	query := `
+		DELETE FROM wishlists
+		WHERE game_id = $2 AND customer_id = (SELECT id FROM customers WHERE user_id = $1)`
+
+	res, err := r.db.ExecContext(ctx, query, userID, gameID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return domain.ErrWishlistItemNotFound
+	}
+	return nil
+}
+
+func (r *psqlWishlistRepository) FetchByUser(ctx context.Context, userID int) ([]domain.WishlistItem, error) {
+	query := `
+		SELECT g.id, g.game_name, g.price, g.stock_level, w.added_at
+		FROM wishlists w
+		JOIN games g ON w.game_id = g.id
+		JOIN customers c ON w.customer_id = c.id
+		WHERE c.user_id = $1 AND g.deleted_at IS NULL
+		ORDER BY w.added_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.WishlistItem
+	for rows.Next() {
+		var i domain.WishlistItem
+		if err := rows.Scan(&i.GameID, &i.GameName, &i.CurrentPrice, &i.StockLevel, &i.AddedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+// FetchWatches returns up to limit wishlist rows of live games, ordered by
+// customer and game, starting after the given row. What a customer pays
+// depends on their region and currency, so price changes can only be told
+// apart once each row is priced.
+func (r *psqlWishlistRepository) FetchWatches(ctx context.Context, afterCustomerID int, afterGameID int, limit int) ([]domain.WishlistWatch, error) {
+	query := `
+		SELECT w.customer_id, c.user_id, g.id, g.game_name,
+		       w.last_seen_price, COALESCE(w.last_seen_currency, ''), w.last_seen_stock, g.price, g.stock_level
+		FROM wishlists w
+		JOIN games g ON w.game_id = g.id
+		JOIN customers c ON w.customer_id = c.id
+		WHERE g.deleted_at IS NULL AND (w.customer_id, w.game_id) > ($1, $2)
+		ORDER BY w.customer_id, w.game_id
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, afterCustomerID, afterGameID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var watches []domain.WishlistWatch
+	for rows.Next() {
+		var w domain.WishlistWatch
+		err := rows.Scan(&w.CustomerID, &w.UserID, &w.GameID, &w.GameName,
+			&w.LastSeenPrice, &w.LastSeenCurrency, &w.LastSeenStock, &w.BasePrice, &w.CurrentStock)
+		if err != nil {
+			return nil, err
+		}
+		watches = append(watches, w)
+	}
+	return watches, rows.Err()
+}
+
+func (r *psqlWishlistRepository) UpdateSnapshot(ctx context.Context, customerID int, gameID int, price domain.Price, stock int) error {
+	query := `UPDATE wishlists SET last_seen_price = $1, last_seen_currency = $2, last_seen_stock = $3 WHERE customer_id = $4 AND game_id = $5`
+	_, err := r.db.ExecContext(ctx, query, price.Amount, price.Currency, stock, customerID, gameID)
+	return err
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("cool-games/internal/wishlist/usecase")
+
+// watchBatchSize is how many wishlist rows CheckChanges handles per timeout.
+const watchBatchSize = 500
+
+type wishlistUsecase struct {
+	wishlistRepo   domain.WishlistRepository
+	gameRepo       domain.GameRepository
+	notifier       domain.Notifier
+	prices         domain.PriceResolver
+	contextTimeout time.Duration
+}
+
+func NewWishlistUsecase(w domain.WishlistRepository, g domain.GameRepository, n domain.Notifier, prices domain.PriceResolver, timeout time.Duration) domain.WishlistUsecase {
+	return &wishlistUsecase{
+		wishlistRepo:   w,
+		gameRepo:       g,
+		notifier:       n,
+		prices:         prices,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *wishlistUsecase) Add(ctx context.Context, userID int, gameID int) error {
+	ctx, span := tracer.Start(ctx, "WishlistUsecase.Add")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	game, err := u.gameRepo.GetByID(c, gameID)
+	if err != nil {
+		return err
+	}
+	price, err := u.prices.PriceFor(c, gameID, game.Price, userID)
+	if err != nil {
+		return err
+	}
+	return u.wishlistRepo.Add(c, userID, gameID, price)
+}
+
+func (u *wishlistUsecase) Remove(ctx context.Context, userID int, gameID int) error {
+	ctx, span := tracer.Start(ctx, "WishlistUsecase.Remove")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+	return u.wishlistRepo.Remove(c, userID, gameID)
+}
+
+func (u *wishlistUsecase) GetWishlist(ctx context.Context, userID int) ([]domain.WishlistItem, error) {
+	ctx, span := tracer.Start(ctx, "WishlistUsecase.GetWishlist")
+	defer span.End()
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	items, err := u.wishlistRepo.FetchByUser(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].LocalPrice, err = u.prices.PriceFor(c, items[i].GameID, items[i].CurrentPrice, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// CheckChanges notifies customers about price drops and restocks of the games
+// on their wishlist since the previous check, then remembers the new values.
+// Prices are compared as each customer pays them, so a regional price cut
+// counts and a change of wallet currency does not. Wishlists are walked in
+// batches of watchBatchSize rows and every batch gets its own timeout, so the
+// sweep is not cut short as the number of wishlists grows.
+func (u *wishlistUsecase) CheckChanges(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "WishlistUsecase.CheckChanges")
+	defer span.End()
+
+	var afterCustomerID, afterGameID int
+	for {
+		watches, err := u.checkBatch(ctx, afterCustomerID, afterGameID)
+		if err != nil {
+			return err
+		}
+		if len(watches) < watchBatchSize {
+			return nil
+		}
+		last := watches[len(watches)-1]
+		afterCustomerID, afterGameID = last.CustomerID, last.GameID
+	}
+}
+
+// checkBatch checks the next batch of watches after the given row and returns
+// the batch it fetched.
+func (u *wishlistUsecase) checkBatch(ctx context.Context, afterCustomerID int, afterGameID int) ([]domain.WishlistWatch, error) {
+	c, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	watches, err := u.wishlistRepo.FetchWatches(c, afterCustomerID, afterGameID, watchBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range watches {
+		price, err := u.prices.PriceFor(c, w.GameID, w.BasePrice, w.UserID)
+		if err != nil {
+			if domain.KindOf(err) == domain.KindInternal {
+				return nil, err
+			}
+			// E.g. the customer's currency lost its exchange rate; the
+			// other customers are still checked.
+			slog.WarnContext(c, "wishlist: cannot price game for customer", "user_id", w.UserID, "game_id", w.GameID, "err", err)
+			continue
+		}
+		lastCurrency := w.LastSeenCurrency
+		if lastCurrency == "" {
+			lastCurrency = u.prices.BaseCurrency()
+		}
+		if price.Amount == w.LastSeenPrice && price.Currency == lastCurrency && w.CurrentStock == w.LastSeenStock {
+			continue
+		}
+
+		if price.Currency == lastCurrency && price.Amount < w.LastSeenPrice {
+			u.notify(c, domain.Notification{
+				UserID:  w.UserID,
+				Type:    domain.NotificationPriceDrop,
+				Title:   fmt.Sprintf("%s is cheaper now", w.GameName),
+				Message: fmt.Sprintf("%s dropped from %.2f to %.2f %s", w.GameName, w.LastSeenPrice, price.Amount, price.Currency),
+			})
+		}
+		if w.LastSeenStock <= 0 && w.CurrentStock > 0 {
+			u.notify(c, domain.Notification{
+				UserID:  w.UserID,
+				Type:    domain.NotificationRestock,
+				Title:   fmt.Sprintf("%s is back in stock", w.GameName),
+				Message: fmt.Sprintf("%s is available again for %.2f %s", w.GameName, price.Amount, price.Currency),
+			})
+		}
+
+		if err := u.wishlistRepo.UpdateSnapshot(c, w.CustomerID, w.GameID, price, w.CurrentStock); err != nil {
+			return nil, err
+		}
+	}
+	return watches, nil
+}
+
+func (u *wishlistUsecase) notify(ctx context.Context, n domain.Notification) {
+	if err := u.notifier.Notify(ctx, n); err != nil {
+		slog.WarnContext(ctx, "wishlist: failed to notify user", "user_id", n.UserID, "err", err)
+	}
+}
+
+// RunWishlistWatcher calls CheckChanges every interval until ctx is cancelled.
+func RunWishlistWatcher(ctx context.Context, us domain.WishlistUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := us.CheckChanges(ctx); err != nil {
+				slog.ErrorContext(ctx, "wishlist: price check failed", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"cool-games/internal/domain"
+	"testing"
+	"time"
+)
+
+// fakeWishlistRepo serves watches in (customer, game) order the way the
+// Postgres repository pages them.
+type fakeWishlistRepo struct {
+	domain.WishlistRepository
+	watches   []domain.WishlistWatch
+	deadlines []time.Time
+	snapshots map[[2]int]domain.Price
+}
+
+func (r *fakeWishlistRepo) FetchWatches(ctx context.Context, afterCustomerID int, afterGameID int, limit int) ([]domain.WishlistWatch, error) {
+	deadline, _ := ctx.Deadline()
+	r.deadlines = append(r.deadlines, deadline)
+
+	var page []domain.WishlistWatch
+	for _, w := range r.watches {
+		if w.CustomerID < afterCustomerID || (w.CustomerID == afterCustomerID && w.GameID <= afterGameID) {
+			continue
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, w)
+	}
+	return page, nil
+}
+
+func (r *fakeWishlistRepo) UpdateSnapshot(ctx context.Context, customerID int, gameID int, price domain.Price, stock int) error {
+	if r.snapshots == nil {
+		r.snapshots = map[[2]int]domain.Price{}
+	}
+	r.snapshots[[2]int{customerID, gameID}] = price
+	return nil
+}
+
+// basePrices charges every customer the base price in EUR.
+type basePrices struct {
+	domain.PriceResolver
+}
+
+func (basePrices) BaseCurrency() string { return "EUR" }
+
+func (basePrices) PriceFor(ctx context.Context, gameID int, basePrice float64, userID int) (domain.Price, error) {
+	return domain.Price{Amount: basePrice, Currency: "EUR", BaseAmount: basePrice}, nil
+}
+
+type recordingNotifier struct {
+	sent []domain.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestCheckChangesWalksEveryBatch(t *testing.T) {
+	repo := &fakeWishlistRepo{}
+	for customer := 1; customer <= 3; customer++ {
+		for game := 1; game <= watchBatchSize/2+1; game++ {
+			repo.watches = append(repo.watches, domain.WishlistWatch{
+				CustomerID: customer, UserID: customer, GameID: game,
+				LastSeenPrice: 10, BasePrice: 8,
+			})
+		}
+	}
+
+	u := NewWishlistUsecase(repo, nil, &recordingNotifier{}, basePrices{}, time.Minute)
+	if err := u.CheckChanges(context.Background()); err != nil {
+		t.Fatalf("CheckChanges: %v", err)
+	}
+
+	if len(repo.snapshots) != len(repo.watches) {
+		t.Errorf("updated %d snapshots, want %d", len(repo.snapshots), len(repo.watches))
+	}
+	wantBatches := len(repo.watches)/watchBatchSize + 1
+	if len(repo.deadlines) != wantBatches {
+		t.Fatalf("fetched %d batches, want %d", len(repo.deadlines), wantBatches)
+	}
+	for i := 1; i < len(repo.deadlines); i++ {
+		if !repo.deadlines[i].After(repo.deadlines[i-1]) {
+			t.Errorf("batch %d shares the deadline of batch %d", i, i-1)
+		}
+	}
+}
+
+func TestCheckChangesNotifications(t *testing.T) {
+	tests := []struct {
+		name  string
+		watch domain.WishlistWatch
+		want  []string
+	}{
+		{
+			name:  "unchanged",
+			watch: domain.WishlistWatch{LastSeenPrice: 10, LastSeenCurrency: "EUR", BasePrice: 10, LastSeenStock: 1, CurrentStock: 1},
+		},
+		{
+			name:  "price drop",
+			watch: domain.WishlistWatch{LastSeenPrice: 10, LastSeenCurrency: "EUR", BasePrice: 8, LastSeenStock: 1, CurrentStock: 1},
+			want:  []string{domain.NotificationPriceDrop},
+		},
+		{
+			name:  "price drop from a snapshot taken before currencies were stored",
+			watch: domain.WishlistWatch{LastSeenPrice: 10, BasePrice: 8, LastSeenStock: 1, CurrentStock: 1},
+			want:  []string{domain.NotificationPriceDrop},
+		},
+		{
+			name:  "price rise",
+			watch: domain.WishlistWatch{LastSeenPrice: 8, LastSeenCurrency: "EUR", BasePrice: 10, LastSeenStock: 1, CurrentStock: 1},
+		},
+		{
+			name:  "other currency",
+			watch: domain.WishlistWatch{LastSeenPrice: 1000, LastSeenCurrency: "JPY", BasePrice: 8, LastSeenStock: 1, CurrentStock: 1},
+		},
+		{
+			name:  "restock",
+			watch: domain.WishlistWatch{LastSeenPrice: 10, LastSeenCurrency: "EUR", BasePrice: 10, LastSeenStock: 0, CurrentStock: 5},
+			want:  []string{domain.NotificationRestock},
+		},
+		{
+			name:  "cheaper and back in stock",
+			watch: domain.WishlistWatch{LastSeenPrice: 10, LastSeenCurrency: "EUR", BasePrice: 5, LastSeenStock: 0, CurrentStock: 5},
+			want:  []string{domain.NotificationPriceDrop, domain.NotificationRestock},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.watch.CustomerID, tt.watch.UserID, tt.watch.GameID = 1, 1, 1
+			repo := &fakeWishlistRepo{watches: []domain.WishlistWatch{tt.watch}}
+			notifier := &recordingNotifier{}
+
+			u := NewWishlistUsecase(repo, nil, notifier, basePrices{}, time.Minute)
+			if err := u.CheckChanges(context.Background()); err != nil {
+				t.Fatalf("CheckChanges: %v", err)
+			}
+
+			var got []string
+			for _, n := range notifier.sent {
+				got = append(got, n.Type)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("notifications = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("notifications = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
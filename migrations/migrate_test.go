@@ -0,0 +1,148 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoadOrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0010_tenth.up.sql":    {Data: []byte("CREATE TABLE ten ();")},
+		"sql/0010_tenth.down.sql":  {Data: []byte("DROP TABLE ten;")},
+		"sql/0002_second.up.sql":   {Data: []byte("CREATE TABLE two ();")},
+		"sql/0002_second.down.sql": {Data: []byte("DROP TABLE two;")},
+		"sql/0001_first.up.sql":    {Data: []byte("CREATE TABLE one ();")},
+		"sql/0001_first.down.sql":  {Data: []byte("DROP TABLE one;")},
+	}
+
+	got, err := load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		version  int64
+		name, up string
+	}{
+		{1, "first", "CREATE TABLE one ();"},
+		{2, "second", "CREATE TABLE two ();"},
+		{10, "tenth", "CREATE TABLE ten ();"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d migrations, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		mig := got[i]
+		if mig.Version != w.version || mig.Name != w.name || mig.Up != w.up {
+			t.Errorf("migration %d = %04d_%s %q, want %04d_%s %q", i, mig.Version, mig.Name, mig.Up, w.version, w.name, w.up)
+		}
+		sum := sha256.Sum256([]byte(w.up))
+		if mig.Checksum != hex.EncodeToString(sum[:]) {
+			t.Errorf("migration %d checksum = %s, want the SHA-256 of its up file", i, mig.Checksum)
+		}
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "missing down",
+			files:   map[string]string{"sql/0001_a.up.sql": "x"},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name:    "empty up",
+			files:   map[string]string{"sql/0001_a.up.sql": "", "sql/0001_a.down.sql": "x"},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name: "version used twice",
+			files: map[string]string{
+				"sql/0001_a.up.sql": "x", "sql/0001_a.down.sql": "x",
+				"sql/0001_b.up.sql": "x", "sql/0001_b.down.sql": "x",
+			},
+			wantErr: "version 1 is used by",
+		},
+		{
+			name:    "no direction",
+			files:   map[string]string{"sql/0001_a.sql": "x"},
+			wantErr: "end in .up.sql or .down.sql",
+		},
+		{
+			name:    "no version",
+			files:   map[string]string{"sql/first.up.sql": "x"},
+			wantErr: "named NNNN_description",
+		},
+		{
+			name:    "version zero",
+			files:   map[string]string{"sql/0000_a.up.sql": "x"},
+			wantErr: "named NNNN_description",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, body := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(body)}
+			}
+			_, err := load(fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// The embedded migrations start at 1 and leave no gaps, so "force 1" always
+// means the original schema.
+func TestEmbeddedMigrations(t *testing.T) {
+	migs, err := load(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, mig := range migs {
+		if mig.Version != int64(i+1) {
+			t.Fatalf("migration %04d_%s follows version %d", mig.Version, mig.Name, i)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	migs, err := load(fstest.MapFS{
+		"sql/0001_a.up.sql": {Data: []byte("CREATE TABLE a ();")}, "sql/0001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"sql/0002_b.up.sql": {Data: []byte("CREATE TABLE b ();")}, "sql/0002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Migrator{migrations: migs}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		applied map[int64]applied
+		wantErr bool
+	}{
+		{"nothing applied", map[int64]applied{}, false},
+		{"unchanged", map[int64]applied{1: {now, migs[0].Checksum}, 2: {now, migs[1].Checksum}}, false},
+		{"recorded before checksums", map[int64]applied{1: {now, ""}}, false},
+		{"unknown newer version", map[int64]applied{1: {now, migs[0].Checksum}, 3: {now, "abc"}}, false},
+		{"edited after applying", map[int64]applied{1: {now, migs[0].Checksum}, 2: {now, migs[0].Checksum}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.verify(tt.applied)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS
+    customer_game_library,
+    ledger,
+    orders,
+    game_quantity_history,
+    game_genres,
+    games,
+    admins,
+    publishers,
+    customers,
+    genres,
+    developers,
+    users;
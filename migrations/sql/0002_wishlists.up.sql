@@ -0,0 +1,10 @@
+-- Wishlists remember the price and stock last reported to the customer, so
+-- the watcher only notifies on a change.
+CREATE TABLE wishlists (
+    customer_id INT REFERENCES customers(id) ON DELETE CASCADE,
+    game_id INT REFERENCES games(id),
+    last_seen_price NUMERIC(10, 2) NOT NULL,
+    last_seen_stock INT NOT NULL,
+    added_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (customer_id, game_id)
+);
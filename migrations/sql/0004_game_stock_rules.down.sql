@@ -0,0 +1,3 @@
+ALTER TABLE game_quantity_history DROP COLUMN is_automatic;
+
+DROP TABLE game_stock_rules;
@@ -0,0 +1,10 @@
+-- Low-stock alerts and auto-restock. Automatic restocks are told apart from
+-- manual ones in the stock history.
+CREATE TABLE game_stock_rules (
+    game_id INT PRIMARY KEY REFERENCES games(id) ON DELETE CASCADE,
+    low_stock_threshold INT NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    auto_restock_amount INT NOT NULL DEFAULT 0 CHECK (auto_restock_amount >= 0),
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE game_quantity_history ADD COLUMN is_automatic BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,8 @@
+DROP INDEX idx_game_quantity_history_game_date;
+
+ALTER TABLE game_quantity_history ADD COLUMN is_automatic BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE game_quantity_history SET is_automatic = (reason = 'auto_restock');
+
+ALTER TABLE game_quantity_history
+    DROP COLUMN reason,
+    DROP COLUMN actor_user_id;
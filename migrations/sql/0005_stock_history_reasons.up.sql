@@ -0,0 +1,17 @@
+-- Every stock change records why it happened and who made it. Rows written
+-- before this were purchases (negative), restocks (positive) or automatic
+-- restocks.
+ALTER TABLE game_quantity_history
+    ADD COLUMN reason VARCHAR(20) NOT NULL DEFAULT 'correction'
+        CHECK (reason IN ('purchase', 'manual_restock', 'auto_restock', 'refund', 'correction')),
+    ADD COLUMN actor_user_id INT REFERENCES users(id);
+
+UPDATE game_quantity_history SET reason = CASE
+    WHEN is_automatic THEN 'auto_restock'
+    WHEN change_amount < 0 THEN 'purchase'
+    ELSE 'manual_restock'
+END;
+
+ALTER TABLE game_quantity_history DROP COLUMN is_automatic;
+
+CREATE INDEX idx_game_quantity_history_game_date ON game_quantity_history (game_id, transaction_date);
@@ -0,0 +1,2 @@
+-- Bumped on every update; the ETag and If-Match are built from it.
+ALTER TABLE games ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+CREATE TABLE game_audit_log (
+    id SERIAL PRIMARY KEY,
+    game_id INT REFERENCES games(id) ON DELETE CASCADE,
+    actor_user_id INT REFERENCES users(id),
+    action VARCHAR(20) NOT NULL,
+    changed_fields TEXT[] NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_game_audit_log_game ON game_audit_log (game_id, created_at DESC);
@@ -0,0 +1,18 @@
+-- The files themselves live in the blob store under storage_key and
+-- thumbnail_key.
+CREATE TABLE game_media (
+    id SERIAL PRIMARY KEY,
+    game_id INT REFERENCES games(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('cover', 'screenshot')),
+    url VARCHAR(512) NOT NULL,
+    thumbnail_url VARCHAR(512) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    storage_key VARCHAR(512) NOT NULL,
+    thumbnail_key VARCHAR(512) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_game_media_game ON game_media (game_id);
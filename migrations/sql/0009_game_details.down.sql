@@ -0,0 +1,9 @@
+ALTER TABLE games
+    DROP COLUMN short_description,
+    DROP COLUMN description,
+    DROP COLUMN min_requirements,
+    DROP COLUMN recommended_requirements,
+    DROP COLUMN age_rating_system,
+    DROP COLUMN age_rating,
+    DROP COLUMN min_age,
+    DROP COLUMN languages;
@@ -0,0 +1,12 @@
+ALTER TABLE games
+    ADD COLUMN short_description VARCHAR(300) NOT NULL DEFAULT '',
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN min_requirements JSONB,
+    ADD COLUMN recommended_requirements JSONB,
+    ADD COLUMN age_rating_system VARCHAR(4) NOT NULL DEFAULT '' CHECK (age_rating_system IN ('', 'PEGI', 'ESRB')),
+    ADD COLUMN age_rating VARCHAR(4) NOT NULL DEFAULT '',
+    ADD COLUMN min_age INT,
+    ADD COLUMN languages TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_games_min_age ON games(min_age);
+CREATE INDEX idx_games_languages ON games USING GIN (languages);
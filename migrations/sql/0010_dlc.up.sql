@@ -0,0 +1,4 @@
+-- A DLC is a game whose parent_game_id is the base game.
+ALTER TABLE games ADD COLUMN parent_game_id INT REFERENCES games(id);
+
+CREATE INDEX idx_games_parent ON games(parent_game_id) WHERE parent_game_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN bundle_id;
+
+DROP TABLE bundle_games, bundles;
@@ -0,0 +1,17 @@
+CREATE TABLE bundles (
+    id SERIAL PRIMARY KEY,
+    publisher_id INT NOT NULL REFERENCES publishers(id),
+    bundle_name VARCHAR(255) NOT NULL,
+    price NUMERIC(10, 2) NOT NULL CHECK (price > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE bundle_games (
+    bundle_id INT REFERENCES bundles(id) ON DELETE CASCADE,
+    game_id INT REFERENCES games(id),
+    PRIMARY KEY (bundle_id, game_id)
+);
+
+-- The order lines of a bundle purchase point at the bundle.
+ALTER TABLE orders ADD COLUMN bundle_id INT REFERENCES bundles(id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+DROP TABLE game_price_history;
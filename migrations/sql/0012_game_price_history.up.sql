@@ -0,0 +1,11 @@
+-- Games without history fall back to their current price for the 30-day
+-- lowest price, so existing games need no backfill.
+CREATE TABLE game_price_history (
+    id SERIAL PRIMARY KEY,
+    game_id INT NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    price NUMERIC(10, 2) NOT NULL,
+    actor_user_id INT REFERENCES users(id),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_game_price_history_game_date ON game_price_history (game_id, changed_at);
@@ -0,0 +1,11 @@
+DROP TABLE game_regional_prices, exchange_rates;
+
+ALTER TABLE ledger DROP COLUMN currency;
+
+ALTER TABLE orders
+    DROP COLUMN currency,
+    DROP COLUMN base_amount;
+
+ALTER TABLE customers
+    DROP COLUMN region,
+    DROP COLUMN currency;
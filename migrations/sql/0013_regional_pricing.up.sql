@@ -0,0 +1,27 @@
+-- A NULL currency means the base currency, which is what every existing
+-- customer, order and ledger row is in.
+ALTER TABLE customers
+    ADD COLUMN region VARCHAR(3) NOT NULL DEFAULT '',
+    ADD COLUMN currency CHAR(3);
+
+ALTER TABLE orders
+    ADD COLUMN currency CHAR(3),
+    ADD COLUMN base_amount NUMERIC(12, 2);
+
+ALTER TABLE ledger ADD COLUMN currency CHAR(3);
+
+CREATE TABLE exchange_rates (
+    currency CHAR(3) PRIMARY KEY,
+    rate NUMERIC(18, 8) NOT NULL CHECK (rate > 0), -- units of currency per unit of BASE_CURRENCY
+    updated_by INT REFERENCES users(id),
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE game_regional_prices (
+    game_id INT REFERENCES games(id) ON DELETE CASCADE,
+    region VARCHAR(3) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    price NUMERIC(10, 2) NOT NULL CHECK (price > 0),
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (game_id, region)
+);
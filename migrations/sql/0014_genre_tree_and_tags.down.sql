@@ -0,0 +1,3 @@
+DROP TABLE game_tags, tags;
+
+ALTER TABLE genres DROP COLUMN parent_id;
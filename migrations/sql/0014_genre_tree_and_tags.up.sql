@@ -0,0 +1,20 @@
+ALTER TABLE genres ADD COLUMN parent_id INT REFERENCES genres(id);
+
+CREATE INDEX idx_genres_parent ON genres(parent_id);
+
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    tag_name VARCHAR(32) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per user who applied the tag; counts are derived from it
+CREATE TABLE game_tags (
+    game_id INT REFERENCES games(id) ON DELETE CASCADE,
+    tag_id INT REFERENCES tags(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (game_id, tag_id, user_id)
+);
+
+CREATE INDEX idx_game_tags_tag ON game_tags (tag_id, game_id);
@@ -0,0 +1,5 @@
+ALTER TABLE game_genres DROP CONSTRAINT game_genres_genre_id_fkey;
+ALTER TABLE game_genres ADD CONSTRAINT game_genres_genre_id_fkey
+    FOREIGN KEY (genre_id) REFERENCES genres(id) ON DELETE CASCADE;
+
+ALTER TABLE genres DROP COLUMN slug;
@@ -0,0 +1,19 @@
+-- Slugs follow domain.Slugify: lowercase ASCII letters and digits, with any
+-- other run of characters turned into a single dash. Names that slugify to
+-- nothing, or to a slug another genre already has, get the id appended.
+ALTER TABLE genres ADD COLUMN slug VARCHAR(100);
+
+UPDATE genres SET slug = trim(BOTH '-' FROM regexp_replace(lower(genre_name), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE genres g SET slug = trim(BOTH '-' FROM g.slug || '-' || g.id)
+WHERE g.slug = ''
+   OR EXISTS (SELECT 1 FROM genres o WHERE o.slug = g.slug AND o.id < g.id);
+
+ALTER TABLE genres ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE genres ADD CONSTRAINT genres_slug_key UNIQUE (slug);
+
+-- Deleting a genre that games use now needs force or a merge, so the
+-- database must not cascade it away.
+ALTER TABLE game_genres DROP CONSTRAINT game_genres_genre_id_fkey;
+ALTER TABLE game_genres ADD CONSTRAINT game_genres_genre_id_fkey
+    FOREIGN KEY (genre_id) REFERENCES genres(id);
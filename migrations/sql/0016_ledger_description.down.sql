@@ -0,0 +1 @@
+ALTER TABLE ledger DROP COLUMN description;
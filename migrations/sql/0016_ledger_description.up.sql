@@ -0,0 +1,2 @@
+-- Top-ups and purchases say what they were for in the customer's statement.
+ALTER TABLE ledger ADD COLUMN description VARCHAR(255) NOT NULL DEFAULT '';
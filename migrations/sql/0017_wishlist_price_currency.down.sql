@@ -0,0 +1 @@
+ALTER TABLE wishlists DROP COLUMN last_seen_currency;
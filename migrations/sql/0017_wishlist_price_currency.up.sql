@@ -0,0 +1,3 @@
+-- Wishlists remember the price the customer was shown, in their wallet
+-- currency. Existing snapshots hold base prices, which NULL stands for.
+ALTER TABLE wishlists ADD COLUMN last_seen_currency CHAR(3);